@@ -0,0 +1,37 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// An AppendDiffer diffs successive versions of a sequence of lines that
+// mostly grows at the end, like a log file that is polled.
+// It retains the lines of the previous version and the scratch space of its
+// Differ between calls.
+// The zero value is ready to use and starts with an empty version.
+type AppendDiffer struct {
+	prev []string
+	d    Differ
+}
+
+// Update returns the changes from the previous version to lines and keeps
+// lines as the new previous version. lines must not be modified afterwards.
+//
+// If the previous version is a prefix of lines, the appended lines are
+// returned as a single insertion without running the diff algorithm.
+// The prefix check compares the lines of the previous version in order and
+// stops at the first difference. If the head changed, for example because
+// the file was truncated or rotated, the full diff is computed instead.
+func (d *AppendDiffer) Update(lines []string) []Change {
+	prev := d.prev
+	d.prev = lines
+	n, m := len(prev), len(lines)
+	data := &stringSlices{prev, lines}
+	if a, _, differ := FirstDiff(n, m, data); a == n {
+		if !differ {
+			return nil
+		}
+		return []Change{{n, n, 0, m - n}}
+	}
+	return d.d.Diff(n, m, data)
+}
@@ -0,0 +1,31 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestAppendDiffer(t *testing.T) {
+	var d diff.AppendDiffer
+	steps := []struct {
+		lines  []string
+		expect []diff.Change
+	}{
+		{[]string{"start"}, []diff.Change{{0, 0, 0, 1}}},
+		{[]string{"start", "one", "two"}, []diff.Change{{1, 1, 0, 2}}},
+		{[]string{"start", "one", "two"}, nil},
+		// rotated
+		{[]string{"one", "two", "three"}, []diff.Change{{0, 0, 1, 0}, {3, 2, 0, 1}}},
+		// truncated
+		{[]string{"one"}, []diff.Change{{1, 1, 2, 0}}},
+	}
+	for i, s := range steps {
+		if res := d.Update(s.lines); !diffsEqual(res, s.expect) {
+			t.Error("step", i, "expected", s.expect, "got", res)
+		}
+	}
+}
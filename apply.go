@@ -0,0 +1,83 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import "fmt"
+
+// An ApplyError reports a line of the base that does not match the patch.
+type ApplyError struct {
+	// Line is the 1-based line number in the base. The patch expects the
+	// base to match input a at the checked lines, so it is also the line
+	// number in a.
+	Line int
+	// Expected is the line of input a the patch expects.
+	Expected string
+	// Got is the line found in the base, empty if the base ends before Line.
+	Got string
+}
+
+func (e *ApplyError) Error() string {
+	return fmt.Sprintf("diff: apply: line %d: expected %q got %q", e.Line, e.Expected, e.Got)
+}
+
+// ApplyLines applies the changes from the lines a to b to base, which is
+// expected to match a around the changes, like patch applies a unified diff.
+// The deleted lines of each change and up to context lines before and after
+// it must equal the lines of a at the same positions. Other lines of base are
+// kept as they are. An *ApplyError is returned for the first line that does
+// not match. To revert, apply the inverted changes with a and b swapped.
+func ApplyLines(base []string, changes []Change, a, b []string, context int) ([]string, error) {
+	for _, h := range Hunks(len(a), len(b), context, changes) {
+		if i := len(base); h.AStart > i {
+			// an insertion past the end of base
+			return nil, &ApplyError{i + 1, a[i], ""}
+		}
+		for i := h.AStart; i < h.AStart+h.ALen; i++ {
+			if i >= len(base) {
+				return nil, &ApplyError{i + 1, a[i], ""}
+			}
+			if base[i] != a[i] {
+				return nil, &ApplyError{i + 1, a[i], base[i]}
+			}
+		}
+	}
+	return Apply(base, b, changes), nil
+}
+
+// Apply returns input b rebuilt from a and the changes from a to b. The
+// unchanged runs are copied from a and the inserted elements from b, so b
+// only needs to hold valid elements at the inserted positions.
+// The changes must be ordered by ascending positions as returned by this package.
+func Apply[T any](a, b []T, changes []Change) []T {
+	n := len(a)
+	for _, c := range changes {
+		n += c.Ins - c.Del
+	}
+	res := make([]T, 0, n)
+	x := 0
+	for _, c := range changes {
+		res = append(res, a[x:c.A]...)
+		res = append(res, b[c.B:c.B+c.Ins]...)
+		x = c.A + c.Del
+	}
+	return append(res, a[x:]...)
+}
+
+// ApplyInserted is like Apply but takes the elements inserted by each change
+// in ins, as returned by ParseUnifiedLines, instead of input b.
+func ApplyInserted[T any](a []T, changes []Change, ins [][]T) []T {
+	n := len(a)
+	for _, c := range changes {
+		n += c.Ins - c.Del
+	}
+	res := make([]T, 0, n)
+	x := 0
+	for i, c := range changes {
+		res = append(res, a[x:c.A]...)
+		res = append(res, ins[i]...)
+		x = c.A + c.Del
+	}
+	return append(res, a[x:]...)
+}
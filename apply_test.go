@@ -0,0 +1,89 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"errors"
+	"github.com/mb0/diff"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	for _, test := range tests {
+		res := diff.Apply(test.a, test.b, diff.Ints(test.a, test.b))
+		if !slices.Equal(res, test.b) {
+			t.Error(test.name, "expected", test.b, "got", res)
+		}
+		back := diff.Invert(diff.Ints(test.a, test.b))
+		if res := diff.Apply(test.b, test.a, back); !slices.Equal(res, test.a) {
+			t.Error(test.name, "reverted expected", test.a, "got", res)
+		}
+	}
+	a := []string{"a", "b", "c"}
+	for _, test := range []struct {
+		name    string
+		b       []string
+		changes []diff.Change
+	}{
+		{"start", []string{"x", "a", "b", "c"}, []diff.Change{{0, 0, 0, 1}}},
+		{"end", []string{"a", "b", "c", "x", "y"}, []diff.Change{{3, 3, 0, 2}}},
+		{"delete", []string{"a"}, []diff.Change{{1, 1, 2, 0}}},
+		{"all", nil, []diff.Change{{0, 0, 3, 0}}},
+	} {
+		if res := diff.Apply(a, test.b, test.changes); !slices.Equal(res, test.b) {
+			t.Error(test.name, "expected", test.b, "got", res)
+		}
+	}
+}
+
+func TestApplyInserted(t *testing.T) {
+	for _, test := range tests {
+		changes := diff.Ints(test.a, test.b)
+		ins := make([][]int, len(changes))
+		for i, c := range changes {
+			ins[i] = test.b[c.B : c.B+c.Ins]
+		}
+		if res := diff.ApplyInserted(test.a, changes, ins); !slices.Equal(res, test.b) {
+			t.Error(test.name, "expected", test.b, "got", res)
+		}
+	}
+}
+
+func TestApplyLines(t *testing.T) {
+	a := []string{"1", "2", "3", "4", "5", "6"}
+	b := []string{"1", "two", "3", "4", "5", "6", "7"}
+	changes := diff.Diff(len(a), len(b), &stringData{"123456", "1x34567"})
+	// the base differs from a away from the changes
+	base := []string{"1", "2", "3", "four", "5", "6"}
+	res, err := diff.ApplyLines(base, changes, a, b, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{"1", "two", "3", "four", "5", "6", "7"}
+	if !reflect.DeepEqual(res, expect) {
+		t.Error("expected", expect, "got", res)
+	}
+	// revert
+	res, err = diff.ApplyLines(res, diff.Invert(changes), b, a, 1)
+	if err != nil || !reflect.DeepEqual(res, base) {
+		t.Error("expected", base, "got", res, err)
+	}
+	// stale base
+	_, err = diff.ApplyLines([]string{"1", "2", "three"}, changes, a, b, 1)
+	var aerr *diff.ApplyError
+	if !errors.As(err, &aerr) || *aerr != (diff.ApplyError{3, "3", "three"}) {
+		t.Error("expected apply error at line 3 got", err)
+	}
+	_, err = diff.ApplyLines([]string{"1", "2", "3", "4", "5"}, changes, a, b, 1)
+	if !errors.As(err, &aerr) || *aerr != (diff.ApplyError{6, "6", ""}) {
+		t.Error("expected apply error at line 6 got", err)
+	}
+	_, err = diff.ApplyLines([]string{"1", "2", "3", "4"}, changes, a, b, 0)
+	if !errors.As(err, &aerr) || *aerr != (diff.ApplyError{5, "5", ""}) {
+		t.Error("expected apply error at line 5 got", err)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// DiffApprox returns the approximate differences of two long int slices.
+// Both inputs are down-sampled by averaging blocks of factor elements, the
+// block sequences are diffed and the changes are scaled back to the inputs.
+//
+// The result is lossy: changes are aligned to block boundaries and cover whole
+// blocks, and differing blocks with the same average are not reported at all.
+// A larger factor is faster but less precise. A factor less than 2 returns the
+// exact differences.
+func DiffApprox(a, b []int, factor int) []Change {
+	if factor < 2 {
+		return Ints(a, b)
+	}
+	changes := Ints(downsample(a, factor), downsample(b, factor))
+	for i, c := range changes {
+		// the last block may be shorter than factor
+		ai, bi := min(c.A*factor, len(a)), min(c.B*factor, len(b))
+		changes[i] = Change{
+			A: ai, B: bi,
+			Del: min((c.A+c.Del)*factor, len(a)) - ai,
+			Ins: min((c.B+c.Ins)*factor, len(b)) - bi,
+		}
+	}
+	return changes
+}
+
+// downsample returns the averages of consecutive blocks of factor elements.
+func downsample(s []int, factor int) []int {
+	res := make([]int, 0, (len(s)+factor-1)/factor)
+	for i := 0; i < len(s); i += factor {
+		block := s[i:min(i+factor, len(s))]
+		sum := 0
+		for _, v := range block {
+			sum += v
+		}
+		res = append(res, sum/len(block))
+	}
+	return res
+}
@@ -0,0 +1,26 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestDiffApprox(t *testing.T) {
+	a := []int{1, 1, 2, 2, 3, 3, 4, 4, 5}
+	b := []int{1, 1, 2, 2, 9, 9, 4, 4, 5, 6, 7}
+	res := diff.DiffApprox(a, b, 2)
+	echange := []diff.Change{{4, 4, 2, 2}, {9, 10, 0, 1}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	for _, test := range tests {
+		res := diff.DiffApprox(test.a, test.b, 1)
+		if !diffsEqual(res, diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "factor 1 differs from Ints", res)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import "slices"
+
+// BatchData is the input of DiffBatch.
+// EqualBatch sets out[k] to whether element pairs[k][0] of input a equals
+// element pairs[k][1] of input b, for each k in the order of pairs.
+// out has the same length as pairs.
+type BatchData interface {
+	EqualBatch(pairs [][2]int, out []bool)
+}
+
+// DiffBatch returns the differences of data like Diff, but asks for the
+// equality of many pairs at once. It is meant for inputs where comparing a
+// batch of pairs is much cheaper than comparing them one by one, for example
+// when the elements live on a remote store or a GPU and each call has a high
+// fixed cost. For cheap comparisons use Diff instead.
+//
+// DiffBatch uses the greedy forward search and extends the snakes of all
+// diagonals of a wavefront together. Each round compares the next pairs
+// of every diagonal that is still on a snake. Diagonals that matched their
+// whole share ask for twice as many pairs in the next round, so long snakes
+// take few rounds and at most as many pairs as were matched are compared past
+// the end of a snake. The endpoints of every wavefront are kept to trace the
+// edits back, which uses O(D*D) space for D edits.
+func DiffBatch(n, m int, data BatchData) []Change {
+	switch {
+	case n == 0 && m == 0:
+		return nil
+	case n == 0 || m == 0:
+		return []Change{{0, 0, n, m}}
+	}
+	size := n + m
+	off := size + 1
+	v := make([]int, 2*size+3)
+	look := make([]int, 2*size+3)
+	var hist [][]int
+	var active []int
+	var pairs [][2]int
+	var out []bool
+	for d := 0; d <= size; d++ {
+		active = active[:0]
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[off+k-1] < v[off+k+1] {
+				x = v[off+k+1] // down
+			} else {
+				x = v[off+k-1] + 1 // right
+			}
+			v[off+k] = x
+			look[off+k] = 1
+			if x < n && x-k < m {
+				active = append(active, k)
+			}
+		}
+		for len(active) > 0 {
+			pairs = pairs[:0]
+			for _, k := range active {
+				x := v[off+k]
+				for i := 0; i < look[off+k] && x+i < n && x+i-k < m; i++ {
+					pairs = append(pairs, [2]int{x + i, x + i - k})
+				}
+			}
+			out = slices.Grow(out[:0], len(pairs))[:len(pairs)]
+			data.EqualBatch(pairs, out)
+			next, p := active[:0], 0
+			for _, k := range active {
+				x := v[off+k]
+				l := min(look[off+k], n-x, m-x+k)
+				i := 0
+				for i < l && out[p+i] {
+					i++
+				}
+				p += l
+				v[off+k] = x + i
+				if i == l && x+i < n && x+i-k < m {
+					look[off+k] *= 2
+					next = append(next, k)
+				}
+			}
+			active = next
+		}
+		hist = append(hist, slices.Clone(v[off-d:off+d+1]))
+		if k := n - m; -d <= k && k <= d && (d-k)%2 == 0 && v[off+k] >= n {
+			break
+		}
+	}
+	// trace the edits back through the wavefronts
+	c := state{flags: make([]byte, max(n, m))}
+	x, y := n, m
+	for d := len(hist) - 1; d > 0; d-- {
+		prev, k := hist[d-1], x-y
+		pk := k - 1
+		if k == -d || k != d && prev[k-1+d-1] < prev[k+1+d-1] {
+			pk = k + 1
+		}
+		x = prev[pk+d-1]
+		y = x - pk
+		if pk == k+1 {
+			c.flags[y] |= 2
+		} else {
+			c.flags[x] |= 1
+		}
+	}
+	return c.result(n, m)
+}
@@ -0,0 +1,50 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+type batchInts struct {
+	ints
+	calls, pairs int
+}
+
+func (d *batchInts) EqualBatch(pairs [][2]int, out []bool) {
+	d.calls++
+	d.pairs += len(pairs)
+	for k, p := range pairs {
+		out[k] = d.a[p[0]] == d.b[p[1]]
+	}
+}
+
+func TestDiffBatch(t *testing.T) {
+	for _, test := range tests {
+		data := &batchInts{ints: ints{test.a, test.b}}
+		res := diff.DiffBatch(len(test.a), len(test.b), data)
+		if edits(res) != edits(diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "expected minimal edits got", res)
+		}
+		if !validChanges(res, test.a, test.b) {
+			t.Error(test.name, "invalid changes", res)
+		}
+	}
+	a, b := make([]int, 1000), make([]int, 1000)
+	for i := range a {
+		a[i], b[i] = i, i
+	}
+	b[500] = -1
+	data := &batchInts{ints: ints{a, b}}
+	res := diff.DiffBatch(len(a), len(b), data)
+	echange := []diff.Change{{500, 500, 1, 1}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	if data.calls > 40 || data.pairs > 4*len(a) {
+		t.Error("expected few batches got", data.calls, "with", data.pairs, "pairs")
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import "context"
+
+// DiffChan returns a channel that receives the differences of data in order
+// and is closed after the last change.
+// The diff runs in its own goroutine and is computed in full before the first
+// change is sent. A consumer that stops receiving must cancel ctx, which makes
+// the goroutine stop the diff or close the channel and exit instead of
+// blocking forever.
+func DiffChan(ctx context.Context, n, m int, data Data) <-chan Change {
+	ch := make(chan Change)
+	go func() {
+		defer close(ch)
+		changes, err := DiffContext(ctx, n, m, data)
+		if err != nil {
+			return
+		}
+		for _, c := range changes {
+			select {
+			case ch <- c:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,32 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"context"
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestDiffChan(t *testing.T) {
+	for _, test := range tests {
+		var res []diff.Change
+		for c := range diff.DiffChan(context.Background(), len(test.a), len(test.b), &ints{test.a, test.b}) {
+			res = append(res, c)
+		}
+		if !diffsEqual(res, diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "differs from Ints", res)
+		}
+	}
+	a := []int{1, 2, 3, 4, 5, 6}
+	b := []int{0, 2, 0, 4, 0, 6}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := diff.DiffChan(ctx, len(a), len(b), &ints{a, b})
+	<-ch
+	cancel()
+	// the channel is closed after at most one more change
+	for range ch {
+	}
+}
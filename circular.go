@@ -0,0 +1,70 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// DiffCircular returns the rotation of b that is closest to a and the changes
+// from a to b rotated left by rotation, that is b[rotation:] followed by
+// b[:rotation]. It is meant for cyclic data like ring buffers, where the same
+// content starting at a different offset should not count as a change.
+//
+// Only rotations that align an element occurring exactly once in a and b are
+// scored, or that align the first element of a if there are no such elements.
+// Each candidate is scored by its edit distance and the rotation 0 is kept
+// unless another one is strictly closer.
+func DiffCircular(a, b []int) (rotation int, changes []Change) {
+	m := len(b)
+	if len(a) == 0 || m == 0 {
+		return 0, Ints(a, b)
+	}
+	seen := map[int]bool{0: true}
+	cands := []int{0}
+	add := func(i, j int) {
+		r := ((j-i)%m + m) % m
+		if !seen[r] {
+			seen[r] = true
+			cands = append(cands, r)
+		}
+	}
+	ca, ia := intCounts(a)
+	cb, _ := intCounts(b)
+	for j, e := range b {
+		if cb[e] == 1 && ca[e] == 1 {
+			add(ia[e], j)
+		}
+	}
+	if len(cands) == 1 {
+		for j, e := range b {
+			if e == a[0] {
+				add(0, j)
+			}
+		}
+	}
+	rotated := make([]int, m)
+	data := &ints{a, rotated}
+	// the differ keeps the d-path endpoints for all candidates
+	var differ Differ
+	best := -1
+	for _, r := range cands {
+		copy(rotated, b[r:])
+		copy(rotated[m-r:], b[:r])
+		if d := differ.Distance(len(a), m, data); best < 0 || d < best {
+			rotation, best = r, d
+		}
+	}
+	copy(rotated, b[rotation:])
+	copy(rotated[m-rotation:], b[:rotation])
+	return rotation, Ints(a, rotated)
+}
+
+// intCounts returns the number of occurrences of each element of s and the
+// index of its last occurrence.
+func intCounts(s []int) (counts, index map[int]int) {
+	counts, index = make(map[int]int, len(s)), make(map[int]int, len(s))
+	for i, e := range s {
+		counts[e]++
+		index[e] = i
+	}
+	return counts, index
+}
@@ -0,0 +1,35 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestDiffCircular(t *testing.T) {
+	a := []int{1, 2, 3, 4, 5, 6}
+	b := []int{4, 5, 9, 1, 2, 3}
+	rot, res := diff.DiffCircular(a, b)
+	echange := []diff.Change{{5, 5, 1, 1}}
+	if rot != 3 || !diffsEqual(res, echange) {
+		t.Error("expected rotation 3 and", echange, "got", rot, res)
+	}
+	// no unique elements
+	a = []int{1, 1, 2, 2}
+	b = []int{2, 2, 1, 1}
+	if rot, res := diff.DiffCircular(a, b); rot != 2 || res != nil {
+		t.Error("expected rotation 2 without changes got", rot, res)
+	}
+	for _, test := range tests {
+		rot, res := diff.DiffCircular(test.a, test.b)
+		if rot == 0 && !diffsEqual(res, diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "differs from Ints", res)
+		}
+		if edits(res) > edits(diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "rotation", rot, "is worse than none", res)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import "fmt"
+
+// Compose returns the changes from the lines a to c given the changes ab from
+// a to b and bc from b to c, without diffing a and c. A line of a is kept if
+// it is kept by ab and the line of b it becomes is kept by bc. The result is
+// valid but not necessarily minimal, because lines that were changed and
+// restored along the way are reported as changed.
+// An error is returned if ab or bc are out of order or range, or do not turn
+// their inputs into each other.
+func Compose(ab, bc []Change, a, b, c []string) ([]Change, error) {
+	if err := checkChanges(ab, a, b); err != nil {
+		return nil, fmt.Errorf("diff: compose a to b: %v", err)
+	}
+	if err := checkChanges(bc, b, c); err != nil {
+		return nil, fmt.Errorf("diff: compose b to c: %v", err)
+	}
+	// the line of a each line of b was kept from, or -1
+	from := make([]int, len(b))
+	for i := range from {
+		from[i] = -1
+	}
+	for _, p := range matches(len(a), ab) {
+		from[p[1]] = p[0]
+	}
+	var res []Change
+	x, y := 0, 0
+	for _, p := range matches(len(b), bc) {
+		i, j := from[p[0]], p[1]
+		if i < 0 {
+			continue
+		}
+		if x < i || y < j {
+			res = append(res, Change{x, y, i - x, j - y})
+		}
+		x, y = i+1, j+1
+	}
+	if x < len(a) || y < len(c) {
+		res = append(res, Change{x, y, len(a) - x, len(c) - y})
+	}
+	return res, nil
+}
+
+// checkChanges returns an error if changes do not turn the lines a into b.
+func checkChanges(changes []Change, a, b []string) error {
+	x, y := 0, 0
+	for _, c := range changes {
+		if c.A < x || c.B-y != c.A-x || c.Del < 0 || c.Ins < 0 || c.A+c.Del > len(a) || c.B+c.Ins > len(b) {
+			return fmt.Errorf("change %v out of order or range", c)
+		}
+		for ; x < c.A; x, y = x+1, y+1 {
+			if a[x] != b[y] {
+				return fmt.Errorf("unchanged line %d differs", x+1)
+			}
+		}
+		x, y = c.A+c.Del, c.B+c.Ins
+	}
+	if len(a)-x != len(b)-y {
+		return fmt.Errorf("changes do not match the input lengths")
+	}
+	for ; x < len(a); x, y = x+1, y+1 {
+		if a[x] != b[y] {
+			return fmt.Errorf("unchanged line %d differs", x+1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestCompose(t *testing.T) {
+	a := []string{"1", "2", "3", "4"}
+	b := []string{"1", "3", "4", "5"}
+	c := []string{"0", "1", "3", "5"}
+	ab := []diff.Change{{1, 1, 1, 0}, {4, 3, 0, 1}}
+	bc := []diff.Change{{0, 0, 0, 1}, {2, 3, 1, 0}}
+	res, err := diff.Compose(ab, bc, a, b, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	echange := []diff.Change{{0, 0, 0, 1}, {1, 2, 1, 0}, {3, 3, 1, 1}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	if _, err := diff.Compose(nil, bc, a, b, c); err == nil {
+		t.Error("expected error for inconsistent changes")
+	}
+	if _, err := diff.Compose(ab, []diff.Change{{5, 5, 1, 0}}, a, b, c); err == nil {
+		t.Error("expected error for changes out of range")
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Context renders the changes of the lines a and b as the hunks of an old
+// style context diff with n context lines, as printed by diff -c without the
+// file headers. Deleted and inserted lines of a change doing both are marked
+// with "!", others with "-" and "+". A side without changes in a hunk lists
+// only its range. The hunks are grouped like those of Unified.
+// The lines are expected to keep their line endings as returned by SplitLines.
+func Context(a, b []string, changes []Change, n int) string {
+	var buf strings.Builder
+	yield := func(l string) bool {
+		buf.WriteString(l)
+		return true
+	}
+	for _, h := range Hunks(len(a), len(b), n, changes) {
+		buf.WriteString("***************\n")
+		buf.WriteString("*** " + contextRange(h.AStart, h.ALen) + " ****\n")
+		contextSide(yield, a, h.AStart, h.AStart+h.ALen, h.Changes, false)
+		buf.WriteString("--- " + contextRange(h.BStart, h.BLen) + " ----\n")
+		contextSide(yield, b, h.BStart, h.BStart+h.BLen, h.Changes, true)
+	}
+	return buf.String()
+}
+
+// contextSide yields the lines from start to end of one side of a hunk,
+// or nothing if none of the changes touch that side.
+func contextSide(yield func(string) bool, lines []string, start, end int, changes []Change, inB bool) {
+	if !sideChanged(changes, inB) {
+		return
+	}
+	x := start
+	for _, c := range changes {
+		pos, l, other := c.A, c.Del, c.Ins
+		if inB {
+			pos, l, other = c.B, c.Ins, c.Del
+		}
+		prefix := "! "
+		if other == 0 {
+			prefix = "- "
+			if inB {
+				prefix = "+ "
+			}
+		}
+		yieldLines(yield, "  ", lines[x:pos], 0)
+		yieldLines(yield, prefix, lines[pos:pos+l], 0)
+		x = pos + l
+	}
+	yieldLines(yield, "  ", lines[x:end], 0)
+}
+
+// sideChanged returns whether any change deletes lines from a or, if inB is
+// true, inserts lines of b.
+func sideChanged(changes []Change, inB bool) bool {
+	for _, c := range changes {
+		if !inB && c.Del > 0 || inB && c.Ins > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// contextRange formats a hunk range as first and last line. Empty ranges
+// refer to the line before.
+func contextRange(start, length int) string {
+	if length <= 1 {
+		return strconv.Itoa(start + length)
+	}
+	return strconv.Itoa(start+1) + "," + strconv.Itoa(start+length)
+}
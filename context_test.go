@@ -0,0 +1,40 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+// The expected outputs are those of GNU diff -c without the file headers.
+func TestContext(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		a, b   string
+		n      int
+		expect string
+	}{
+		{"two hunks", "a\nb\nc\nd\ne\nf\ng\nh\n", "a\nB\nc\nd\ne\nf\nh\ni\n", 1, "" +
+			"***************\n*** 1,3 ****\n  a\n! b\n  c\n--- 1,3 ----\n  a\n! B\n  c\n" +
+			"***************\n*** 6,8 ****\n  f\n- g\n  h\n--- 6,8 ----\n  f\n  h\n+ i\n"},
+		{"insert", "a\nb\n", "a\nx\nb\n", 3,
+			"***************\n*** 1,2 ****\n--- 1,3 ----\n  a\n+ x\n  b\n"},
+		{"delete", "a\nx\nb\n", "a\nb\n", 3,
+			"***************\n*** 1,3 ****\n  a\n- x\n  b\n--- 1,2 ----\n"},
+		{"empty", "", "x\ny", 3,
+			"***************\n*** 0 ****\n--- 1,2 ----\n+ x\n+ y\n" + diff.NoNewline},
+		{"no newline", "a\nb", "a\nc", 3, "" +
+			"***************\n*** 1,2 ****\n  a\n! b\n" + diff.NoNewline +
+			"--- 1,2 ----\n  a\n! c\n" + diff.NoNewline},
+		{"equal", "a\n", "a\n", 3, ""},
+	} {
+		a, b := diff.SplitLines(test.a), diff.SplitLines(test.b)
+		res := diff.Context(a, b, diff.Strings(a, b), test.n)
+		if res != test.expect {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.name, test.expect, res)
+		}
+	}
+}
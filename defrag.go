@@ -0,0 +1,103 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// Defragment slides pure insertions and deletions of changes along the equal
+// runs around them and merges them with a neighboring change where they meet.
+// The result has the same number of deleted and inserted elements, but fewer
+// and longer unchanged runs. Changes that cannot be merged are kept in place.
+// The changes must be ordered as returned by Diff(n, m, data) and are left
+// unmodified.
+func Defragment(n, m int, data Data, changes []Change) []Change {
+	return slideMerge(n, data, changes, true, -1, 1)
+}
+
+// slideMerge slides each pure insertion or deletion of changes along the
+// equal run around it, trying the directions dirs in turn, and merges it with
+// the neighbor it reaches. A change that reaches no neighbor is kept at its
+// original position if keep is true, or else moved as far as the last
+// direction allows. The changes are left unmodified.
+func slideMerge(n int, data Data, changes []Change, keep bool, dirs ...int) []Change {
+	res := make([]Change, 0, len(changes))
+	// carry is a change merged into the next one
+	var carry Change
+	for i := 0; i < len(changes); i++ {
+		c := changes[i]
+		if carry.Del+carry.Ins > 0 {
+			c = Change{carry.A, carry.B, carry.Del + c.Del, carry.Ins + c.Ins}
+			carry = Change{}
+		}
+		if c.Del > 0 && c.Ins > 0 {
+			res = append(res, c)
+			continue
+		}
+		// start of the equal run before and end of the one after c
+		var pa int
+		if len(res) > 0 {
+			prev := res[len(res)-1]
+			pa = prev.A + prev.Del
+		}
+		na := n
+		if i+1 < len(changes) {
+			na = changes[i+1].A
+		}
+		s, merged := c, false
+		for _, dir := range dirs {
+			s = c
+			if dir < 0 {
+				for s.A > pa && slides(data, s, -1) {
+					s.A--
+					s.B--
+				}
+				if merged = len(res) > 0 && s.A == pa; merged {
+					prev := &res[len(res)-1]
+					prev.Del += s.Del
+					prev.Ins += s.Ins
+					break
+				}
+			} else {
+				for s.A+s.Del < na && slides(data, s, 1) {
+					s.A++
+					s.B++
+				}
+				if merged = i+1 < len(changes) && s.A+s.Del == na; merged {
+					carry = s
+					break
+				}
+			}
+		}
+		if merged {
+			continue
+		}
+		if keep {
+			s = c
+		}
+		res = append(res, s)
+	}
+	return res
+}
+
+// slides returns whether the pure insertion or deletion c can move one
+// element in direction dir, because the element it uncovers equals the
+// element it covers.
+func slides(data Data, c Change, dir int) bool {
+	if dir < 0 {
+		if c.Ins == 0 {
+			return data.Equal(c.A+c.Del-1, c.B-1)
+		}
+		return data.Equal(c.A-1, c.B+c.Ins-1)
+	}
+	return data.Equal(c.A, c.B)
+}
+
+// DiffBoth returns the minimal differences of data as returned by Diff and
+// a defragmented copy of them, from a single run of the algorithm.
+// The minimal changes are canonical and should be used to store or apply
+// patches. The readable changes are meant for display only.
+func DiffBoth(n, m int, data Data) (minimal, readable []Change) {
+	minimal = Diff(n, m, data)
+	readable = Defragment(n, m, data, minimal)
+	return minimal, readable
+}
@@ -0,0 +1,49 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestDefragment(t *testing.T) {
+	// think of 0 as a closing brace and 1 as a blank line
+	a := []int{2, 0, 1, 0}
+	b := []int{0, 1, 1}
+	// a minimal but fragmented diff
+	before := []diff.Change{{0, 0, 1, 0}, {2, 1, 0, 1}, {3, 3, 1, 0}}
+	after := diff.Defragment(len(a), len(b), &ints{a, b}, before)
+	eafter := []diff.Change{{0, 0, 1, 0}, {3, 2, 1, 1}}
+	if !diffsEqual(after, eafter) {
+		t.Error("expected", eafter, "got", after)
+	}
+	if ebefore := []diff.Change{{0, 0, 1, 0}, {2, 1, 0, 1}, {3, 3, 1, 0}}; !diffsEqual(before, ebefore) {
+		t.Error("expected unmodified", ebefore, "got", before)
+	}
+	for _, test := range tests {
+		// the test cases are not fragmented
+		expect := diff.Ints(test.a, test.b)
+		res := diff.Defragment(len(test.a), len(test.b), &ints{test.a, test.b}, diff.Ints(test.a, test.b))
+		if !diffsEqual(res, expect) {
+			t.Error(test.name, "expected", expect, "got", res)
+		}
+	}
+}
+
+func TestDiffBoth(t *testing.T) {
+	for _, test := range tests {
+		data := &ints{test.a, test.b}
+		minimal, readable := diff.DiffBoth(len(test.a), len(test.b), data)
+		expect := diff.Ints(test.a, test.b)
+		if !diffsEqual(minimal, expect) {
+			t.Error(test.name, "expected minimal", expect, "got", minimal)
+		}
+		expect = diff.Defragment(len(test.a), len(test.b), data, expect)
+		if !diffsEqual(readable, expect) {
+			t.Error(test.name, "expected readable", expect, "got", readable)
+		}
+	}
+}
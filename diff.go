@@ -6,6 +6,8 @@
 // The algorithm is described in "An O(ND) Difference Algorithm and its Variations", Eugene Myers, Algorithmica Vol. 1 No. 2, 1986, pp. 251-266.
 package diff
 
+import "strconv"
+
 // A type that satisfies diff.Data can be diffed by this package.
 // It typically has two sequences A and B of comparable elements.
 type Data interface {
@@ -13,27 +15,61 @@ type Data interface {
 	Equal(i, j int) bool
 }
 
+// Prehashed can be implemented by Data that cheaply maintains a hash of both
+// sequences, like a rolling hash in an editor. Diff returns no changes without
+// calling Equal if both sequences have the same length and hash.
+// This is a heuristic: a hash collision hides all differences, so only
+// implement it if the hash is strong enough for the use case.
+type Prehashed interface {
+	Data
+	// Hashes returns the hashes of the sequences a and b.
+	Hashes() (a, b uint64)
+}
+
+// FromFunc returns Data that compares elements with eq, so that any random
+// access sequences can be diffed without defining a type. The lengths are
+// passed to Diff as usual. eq must be deterministic, it may be called more
+// than once for the same pair and must return the same result each time.
+func FromFunc(eq func(i, j int) bool) Data { return funcData(eq) }
+
+// DiffFunc returns the differences of two sequences of length n and m whose
+// elements at i and j are compared with equal, like sort.Slice takes a less
+// function. It is short for Diff(n, m, FromFunc(equal)).
+func DiffFunc(n, m int, equal func(i, j int) bool) []Change {
+	return Diff(n, m, FromFunc(equal))
+}
+
+type funcData func(i, j int) bool
+
+func (f funcData) Equal(i, j int) bool { return f(i, j) }
+
 // ByteStrings returns the differences of two strings in bytes.
 func ByteStrings(a, b string) []Change {
-	return Diff(len(a), len(b), &strings{a, b})
+	pre, suf := trimBytes(a, b)
+	a, b = a[pre:len(a)-suf], b[pre:len(b)-suf]
+	return shift(Diff(len(a), len(b), &byteStrings{a, b}), pre)
 }
 
-type strings struct{ a, b string }
+type byteStrings struct{ a, b string }
 
-func (d *strings) Equal(i, j int) bool { return d.a[i] == d.b[j] }
+func (d *byteStrings) Equal(i, j int) bool { return d.a[i] == d.b[j] }
 
 // Bytes returns the difference of two byte slices
 func Bytes(a, b []byte) []Change {
-	return Diff(len(a), len(b), &bytes{a, b})
+	pre, suf := trimBytes(a, b)
+	a, b = a[pre:len(a)-suf], b[pre:len(b)-suf]
+	return shift(Diff(len(a), len(b), &byteSlices{a, b}), pre)
 }
 
-type bytes struct{ a, b []byte }
+type byteSlices struct{ a, b []byte }
 
-func (d *bytes) Equal(i, j int) bool { return d.a[i] == d.b[j] }
+func (d *byteSlices) Equal(i, j int) bool { return d.a[i] == d.b[j] }
 
 // Ints returns the difference of two int slices
 func Ints(a, b []int) []Change {
-	return Diff(len(a), len(b), &ints{a, b})
+	pre, suf := trimSlices(a, b)
+	a, b = a[pre:len(a)-suf], b[pre:len(b)-suf]
+	return shift(Diff(len(a), len(b), &ints{a, b}), pre)
 }
 
 type ints struct{ a, b []int }
@@ -42,13 +78,26 @@ func (d *ints) Equal(i, j int) bool { return d.a[i] == d.b[j] }
 
 // Runes returns the difference of two rune slices
 func Runes(a, b []rune) []Change {
-	return Diff(len(a), len(b), &runes{a, b})
+	pre, suf := trimSlices(a, b)
+	a, b = a[pre:len(a)-suf], b[pre:len(b)-suf]
+	return shift(Diff(len(a), len(b), &runes{a, b}), pre)
 }
 
 type runes struct{ a, b []rune }
 
 func (d *runes) Equal(i, j int) bool { return d.a[i] == d.b[j] }
 
+// Strings returns the difference of two string slices, like the lines of two files.
+func Strings(a, b []string) []Change {
+	pre, suf := trimSlices(a, b)
+	a, b = a[pre:len(a)-suf], b[pre:len(b)-suf]
+	return shift(Diff(len(a), len(b), &stringSlices{a, b}), pre)
+}
+
+type stringSlices struct{ a, b []string }
+
+func (d *stringSlices) Equal(i, j int) bool { return d.a[i] == d.b[j] }
+
 // Granular merges neighboring changes smaller than the specified granularity.
 // The changes must be ordered by ascending positions as returned by this package.
 func Granular(granularity int, changes []Change) []Change {
@@ -74,18 +123,128 @@ func Granular(granularity int, changes []Change) []Change {
 	return changes[:len(changes)-gap]
 }
 
+// Compact drops changes without deletions or insertions and merges changes
+// that directly follow each other, as may result from post-processing.
+// The changes must be ordered by ascending positions as returned by this package.
+func Compact(changes []Change) []Change {
+	res := changes[:0]
+	for _, c := range changes {
+		if c.Del == 0 && c.Ins == 0 {
+			continue
+		}
+		if l := len(res); l > 0 {
+			prev := &res[l-1]
+			if c.A == prev.A+prev.Del && c.B == prev.B+prev.Ins {
+				prev.Del += c.Del
+				prev.Ins += c.Ins
+				continue
+			}
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+// OnlyInserts returns the insertions of changes. A replacement is split into
+// its deletion at A and its insertion at A+Del, and only the latter is kept.
+func OnlyInserts(changes []Change) []Change {
+	var res []Change
+	for _, c := range changes {
+		if c.Ins > 0 {
+			res = append(res, Change{A: c.A + c.Del, B: c.B, Ins: c.Ins})
+		}
+	}
+	return res
+}
+
+// OnlyDeletes returns the deletions of changes. A replacement is split into
+// its deletion at A and its insertion at A+Del, and only the former is kept.
+func OnlyDeletes(changes []Change) []Change {
+	var res []Change
+	for _, c := range changes {
+		if c.Del > 0 {
+			res = append(res, Change{A: c.A, B: c.B, Del: c.Del})
+		}
+	}
+	return res
+}
+
+// MapPosition returns the position in input b that corresponds to the
+// position aPos in input a. If aPos was deleted or replaced, inChange is true
+// and bPos is the start of the change in b. Positions at or past the end of a
+// map past the end of b.
+// The changes must be ordered by ascending positions as returned by this package.
+func MapPosition(changes []Change, aPos int) (bPos int, inChange bool) {
+	off := 0
+	for _, c := range changes {
+		if aPos < c.A {
+			break
+		}
+		if aPos < c.A+c.Del {
+			return c.B, true
+		}
+		off = c.B + c.Ins - c.A - c.Del
+	}
+	return aPos + off, false
+}
+
+// MapPositionBack is the inverse of MapPosition and returns the position in
+// input a that corresponds to the position bPos in input b.
+func MapPositionBack(changes []Change, bPos int) (aPos int, inChange bool) {
+	off := 0
+	for _, c := range changes {
+		if bPos < c.B {
+			break
+		}
+		if bPos < c.B+c.Ins {
+			return c.A, true
+		}
+		off = c.A + c.Del - c.B - c.Ins
+	}
+	return bPos + off, false
+}
+
 // Diff returns the differences of data.
 // data.Equal is called repeatedly with 0<=i<n and 0<=j<m
+// If both inputs are empty the result is nil, if only one is empty the result
+// is a single change deleting or inserting all of the other.
 func Diff(n, m int, data Data) []Change {
-	c := &context{data: data}
-	if n > m {
-		c.flags = make([]byte, n)
-	} else {
-		c.flags = make([]byte, m)
+	var d Differ
+	return d.Diff(n, m, data)
+}
+
+// DiffBidirectional returns the changes from a to b and from b to a of data
+// from a single diff, for example for undo and redo. The backward changes are
+// Invert(forward), so both are always consistent.
+func DiffBidirectional(n, m int, data Data) (forward, backward []Change) {
+	forward = Diff(n, m, data)
+	return forward, Invert(forward)
+}
+
+// FirstDiff returns the positions where the sequences of data first differ.
+// It only compares the common prefix and does not run the diff algorithm.
+// If one sequence is a prefix of the other, the positions are at the end of
+// the shorter one. differ is false if both sequences are equal.
+func FirstDiff(n, m int, data Data) (a, b int, differ bool) {
+	for a < n && a < m && data.Equal(a, a) {
+		a++
 	}
-	c.max = n + m + 1
-	c.compare(0, 0, n, m)
-	return c.result(n, m)
+	return a, a, a < n || a < m
+}
+
+// Identical returns whether the sequences of data have the same length and
+// equal elements at each index. It is O(min(n, m)), stops at the first
+// difference and does not run the diff algorithm.
+func Identical(n, m int, data Data) bool {
+	if n != m {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if !data.Equal(i, i) {
+			return false
+		}
+	}
+	return true
 }
 
 // A Change contains one or more deletions or inserts
@@ -96,15 +255,102 @@ type Change struct {
 	Ins  int // insert Ins elements from input b
 }
 
-type context struct {
+// Kind classifies a change or segment.
+type Kind int
+
+const (
+	Equal   Kind = iota // no elements deleted or inserted
+	Delete              // only elements deleted
+	Insert              // only elements inserted
+	Replace             // elements deleted and inserted
+)
+
+var kindNames = [...]string{"equal", "delete", "insert", "replace"}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "kind(" + strconv.Itoa(int(k)) + ")"
+	}
+	return kindNames[k]
+}
+
+// Kind returns whether c deletes, inserts or replaces elements.
+func (c Change) Kind() Kind {
+	switch {
+	case c.Del > 0 && c.Ins > 0:
+		return Replace
+	case c.Del > 0:
+		return Delete
+	case c.Ins > 0:
+		return Insert
+	}
+	return Equal
+}
+
+// Flip returns the change with the roles of a and b swapped.
+// It is the corresponding change in the diff of b and a.
+func (c Change) Flip() Change {
+	return Change{A: c.B, B: c.A, Del: c.Ins, Ins: c.Del}
+}
+
+// Invert returns the changes of the diff of b and a given the changes of the
+// diff of a and b, with each change flipped. The result is a valid and minimal
+// diff of b and a, but it can differ from the one Diff returns for ambiguous
+// inputs where more than one shortest edit script exists.
+func Invert(changes []Change) []Change {
+	if changes == nil {
+		return nil
+	}
+	res := make([]Change, len(changes))
+	for i, c := range changes {
+		res[i] = c.Flip()
+	}
+	return res
+}
+
+// Less returns whether c orders before o.
+// Changes are ordered by A, then B, then Del and then Ins.
+func (c Change) Less(o Change) bool {
+	switch {
+	case c.A != o.A:
+		return c.A < o.A
+	case c.B != o.B:
+		return c.B < o.B
+	case c.Del != o.Del:
+		return c.Del < o.Del
+	}
+	return c.Ins < o.Ins
+}
+
+// ChangeSet attaches the methods of sort.Interface to []Change, in the order of Change.Less.
+type ChangeSet []Change
+
+func (s ChangeSet) Len() int           { return len(s) }
+func (s ChangeSet) Less(i, j int) bool { return s[i].Less(s[j]) }
+func (s ChangeSet) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type state struct {
 	data  Data
+	alloc Allocator
 	flags []byte // element bits 1 delete, 2 insert
 	max   int
 	// forward and reverse d-path endpoint x components
 	forward, reverse []int
+	// runes holds the decoded runes of Differ.LCSLen
+	runes ints
+	// centered keeps the d-path slices centered on the midpoints of the
+	// region and grows them with d instead of sizing them for all diagonals
+	centered bool
+	// cancel reports whether to stop searching if not nil
+	cancel func() bool
+	// dlimit stops searching past this d if positive
+	dlimit int
+	// forward endpoints of each d collected by Trace if tracing
+	tracing bool
+	trace   [][]int
 }
 
-func (c *context) compare(aoffset, boffset, alimit, blimit int) {
+func (c *state) compare(aoffset, boffset, alimit, blimit int) {
 	// eat common prefix
 	for aoffset < alimit && boffset < blimit && c.data.Equal(aoffset, boffset) {
 		aoffset++
@@ -131,29 +377,99 @@ func (c *context) compare(aoffset, boffset, alimit, blimit int) {
 		}
 		return
 	}
+	// small regions are cheaper to solve directly
+	if (alimit-aoffset)*(blimit-boffset) <= smallRegion {
+		c.quadratic(aoffset, boffset, alimit, blimit)
+		return
+	}
 	x, y := c.findMiddleSnake(aoffset, boffset, alimit, blimit)
+	if x < 0 {
+		// canceled, replace the whole region
+		for i := aoffset; i < alimit; i++ {
+			c.flags[i] |= 1
+		}
+		for j := boffset; j < blimit; j++ {
+			c.flags[j] |= 2
+		}
+		return
+	}
 	c.compare(aoffset, boffset, x, y)
 	c.compare(x, y, alimit, blimit)
 }
 
-func (c *context) findMiddleSnake(aoffset, boffset, alimit, blimit int) (int, int) {
+// smallRegion is the largest product of the region lengths solved by quadratic.
+const smallRegion = 64
+
+// quadratic flags a minimal edit script of the region using the classic
+// dynamic programming table of common subsequence lengths.
+func (c *state) quadratic(aoffset, boffset, alimit, blimit int) {
+	n, m := alimit-aoffset, blimit-boffset
+	w := m + 1
+	// lcs[i*w+j] is the length of the lcs of the region suffixes at i and j
+	// with n*m <= smallRegion the table has at most 2*smallRegion+2 entries
+	var table [2*smallRegion + 2]int
+	lcs := table[:(n+1)*w]
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			k := i*w + j
+			if c.data.Equal(aoffset+i, boffset+j) {
+				lcs[k] = lcs[k+w+1] + 1
+			} else {
+				lcs[k] = max(lcs[k+w], lcs[k+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < n && j < m {
+		k := i*w + j
+		switch {
+		case lcs[k] == lcs[k+w+1]+1 && c.data.Equal(aoffset+i, boffset+j):
+			i++
+			j++
+		case lcs[k+1] >= lcs[k+w]:
+			c.flags[boffset+j] |= 2
+			j++
+		default:
+			c.flags[aoffset+i] |= 1
+			i++
+		}
+	}
+	for ; i < n; i++ {
+		c.flags[aoffset+i] |= 1
+	}
+	for ; j < m; j++ {
+		c.flags[boffset+j] |= 2
+	}
+}
+
+// findMiddleSnake returns the start of the middle snake of the region,
+// or -1, -1 if the search was canceled or reached the d limit.
+func (c *state) findMiddleSnake(aoffset, boffset, alimit, blimit int) (int, int) {
 	// midpoints
 	fmid := aoffset - boffset
 	rmid := alimit - blimit
 	// correct offset in d-path slices
 	foff := c.max - fmid
 	roff := c.max - rmid
+	if c.centered {
+		c.growCentered(0)
+		foff, roff = len(c.forward)/2-fmid, len(c.reverse)/2-rmid
+	} else {
+		c.grow(2 * c.max)
+	}
 	isodd := (rmid-fmid)&1 != 0
 	maxd := (alimit - aoffset + blimit - boffset + 2) / 2
-	// allocate when first used
-	if c.forward == nil {
-		c.forward = make([]int, 2*c.max)
-		c.reverse = make([]int, 2*c.max)
-	}
-	c.forward[c.max+1] = aoffset
-	c.reverse[c.max-1] = alimit
+	c.forward[foff+fmid+1] = aoffset
+	c.reverse[roff+rmid-1] = alimit
 	var x, y int
 	for d := 0; d <= maxd; d++ {
+		if c.centered && len(c.forward) < 2*d+4 {
+			c.growCentered(d)
+			foff, roff = len(c.forward)/2-fmid, len(c.reverse)/2-rmid
+		}
+		if c.cancel != nil && c.cancel() || c.dlimit > 0 && d > c.dlimit {
+			return -1, -1
+		}
 		// forward search
 		for k := fmid - d; k <= fmid+d; k += 2 {
 			if k == fmid-d || k != fmid+d && c.forward[foff+k+1] > c.forward[foff+k-1] {
@@ -169,10 +485,16 @@ func (c *context) findMiddleSnake(aoffset, boffset, alimit, blimit int) (int, in
 			c.forward[foff+k] = x
 			if isodd && k > rmid-d && k < rmid+d {
 				if c.reverse[roff+k] <= c.forward[foff+k] {
+					if c.tracing {
+						c.traceForward(foff, fmid-d, k)
+					}
 					return x, x - k
 				}
 			}
 		}
+		if c.tracing {
+			c.traceForward(foff, fmid-d, fmid+d)
+		}
 		// reverse search x,y correspond to u,v
 		for k := rmid - d; k <= rmid+d; k += 2 {
 			if k == rmid+d || k != rmid-d && c.reverse[roff+k-1] < c.reverse[roff+k+1] {
@@ -198,7 +520,73 @@ func (c *context) findMiddleSnake(aoffset, boffset, alimit, blimit int) (int, in
 	panic("should never be reached")
 }
 
-func (c *context) result(n, m int) (res []Change) {
+// traceForward appends the forward endpoints of the diagonals from k to last.
+func (c *state) traceForward(foff, k, last int) {
+	var row []int
+	for ; k <= last; k += 2 {
+		row = append(row, c.forward[foff+k])
+	}
+	c.trace = append(c.trace, row)
+}
+
+// grow allocates the d-path slices when first used or shorter than size.
+func (c *state) grow(size int) {
+	if len(c.forward) < size {
+		c.free()
+		if c.alloc != nil {
+			c.forward = c.alloc.Alloc(size)
+			c.reverse = c.alloc.Alloc(size)
+		} else {
+			c.forward = make([]int, size)
+			c.reverse = make([]int, size)
+		}
+	}
+}
+
+// growCentered grows the centered d-path slices to hold the diagonals of
+// at least d around the midpoints and keeps their endpoints centered.
+func (c *state) growCentered(d int) {
+	if len(c.forward) >= 2*d+4 {
+		return
+	}
+	size := max(2*len(c.forward), 2*d+4, 64)
+	shift := size/2 - len(c.forward)/2
+	forward, reverse := make([]int, size), make([]int, size)
+	copy(forward[shift:], c.forward)
+	copy(reverse[shift:], c.reverse)
+	c.free()
+	c.forward, c.reverse = forward, reverse
+}
+
+// free returns the d-path slices to the allocator.
+func (c *state) free() {
+	if c.alloc != nil && c.forward != nil {
+		c.alloc.Free(c.forward)
+		c.alloc.Free(c.reverse)
+	}
+	c.forward, c.reverse = nil, nil
+}
+
+// BuildResult returns the changes described by flags, for alignments computed
+// outside of this package. Bit 1 of flags[i] marks element i of input a as
+// deleted and bit 2 of flags[j] marks element j of input b as inserted, so
+// flags needs a length of at least the larger of n and m. All unmarked
+// elements are matched in order, so the number of unmarked elements must be
+// the same in a and b. It panics if flags is too short or has other bits set.
+func BuildResult(flags []byte, n, m int) []Change {
+	if len(flags) < max(n, m) {
+		panic("diff: flags shorter than inputs")
+	}
+	for _, f := range flags {
+		if f&^3 != 0 {
+			panic("diff: invalid flag " + strconv.Itoa(int(f)))
+		}
+	}
+	c := state{flags: flags}
+	return c.result(n, m)
+}
+
+func (c *state) result(n, m int) (res []Change) {
 	var x, y int
 	for x < n || y < m {
 		if x < n && y < m && c.flags[x]&1 == 0 && c.flags[y]&2 == 0 {
@@ -6,6 +6,11 @@ package diff_test
 
 import (
 	"github.com/mb0/diff"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
 	"testing"
 )
 
@@ -64,6 +69,21 @@ var tests = []testcase{
 			{6, 5, 0, 1},
 		},
 	},
+	{"empty a",
+		[]int{},
+		[]int{1, 2, 3},
+		[]diff.Change{{0, 0, 0, 3}},
+	},
+	{"empty b",
+		[]int{1, 2, 3},
+		nil,
+		[]diff.Change{{0, 0, 3, 0}},
+	},
+	{"both empty",
+		nil,
+		[]int{},
+		[]diff.Change{},
+	},
 	// note: input is ambiguous
 	// first two traces differ from fig.1
 	// it still is a lcs and ses path
@@ -110,7 +130,7 @@ func TestDiffBA(t *testing.T) {
 		}
 		for i, c := range test.res {
 			// flip change data also
-			rc := diff.Change{c.B, c.A, c.Ins, c.Del}
+			rc := c.Flip()
 			if rc != res[i] {
 				t.Error(test.name, "expected ", rc, "got", res[i])
 			}
@@ -118,6 +138,144 @@ func TestDiffBA(t *testing.T) {
 	}
 }
 
+type panicData struct{}
+
+func (panicData) Equal(i, j int) bool { panic("unexpected call to Equal") }
+
+func TestInvert(t *testing.T) {
+	for _, test := range tests {
+		res := diff.Invert(diff.Ints(test.a, test.b))
+		// the paper example has more than one shortest edit script
+		if test.name != "paper fig. 1" && !diffsEqual(res, diff.Ints(test.b, test.a)) {
+			t.Error(test.name, "expected", diff.Ints(test.b, test.a), "got", res)
+		}
+		if !validChanges(res, test.b, test.a) || edits(res) != edits(diff.Ints(test.b, test.a)) {
+			t.Error(test.name, "invalid or not minimal", res)
+		}
+	}
+}
+
+func TestDiffEmpty(t *testing.T) {
+	if res := diff.Diff(0, 0, panicData{}); res != nil {
+		t.Error("expected nil got", res)
+	}
+	if res := diff.Diff(0, 2, panicData{}); !diffsEqual(res, []diff.Change{{0, 0, 0, 2}}) {
+		t.Error("expected one insert got", res)
+	}
+	if res := diff.Diff(2, 0, panicData{}); !diffsEqual(res, []diff.Change{{0, 0, 2, 0}}) {
+		t.Error("expected one delete got", res)
+	}
+}
+
+type hashedData struct {
+	panicData
+	a, b uint64
+}
+
+func (d hashedData) Hashes() (uint64, uint64) { return d.a, d.b }
+
+func TestDiffPrehashed(t *testing.T) {
+	if res := diff.Diff(3, 3, hashedData{a: 7, b: 7}); res != nil {
+		t.Error("expected nil got", res)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Equal to be called for different hashes")
+		}
+	}()
+	diff.Diff(3, 3, hashedData{a: 7, b: 8})
+}
+
+func TestFromFunc(t *testing.T) {
+	for _, test := range tests {
+		data := diff.FromFunc(func(i, j int) bool { return test.a[i] == test.b[j] })
+		res := diff.Diff(len(test.a), len(test.b), data)
+		if !diffsEqual(res, diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "differs from Ints", res)
+		}
+	}
+}
+
+func TestDiffBidirectional(t *testing.T) {
+	for _, test := range tests {
+		forward, backward := diff.DiffBidirectional(len(test.a), len(test.b), &ints{test.a, test.b})
+		if !diffsEqual(forward, diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "forward differs from Ints", forward)
+		}
+		if !validChanges(backward, test.b, test.a) {
+			t.Error(test.name, "backward does not turn b into a", backward)
+		}
+	}
+}
+
+func TestBuildResult(t *testing.T) {
+	// a deletes 1 and 3, b inserts 0 and 1
+	flags := []byte{2, 3, 0, 1}
+	echange := []diff.Change{{0, 0, 0, 2}, {1, 3, 1, 0}, {3, 4, 1, 0}}
+	if res := diff.BuildResult(flags, 4, 4); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for invalid flag")
+		}
+	}()
+	diff.BuildResult([]byte{4}, 1, 1)
+}
+
+func TestDiffFunc(t *testing.T) {
+	a := []float64{1, 2, 3.0001, 4, 5}
+	b := []float64{0.9999, 2, 3, 4.5, 5}
+	res := diff.DiffFunc(len(a), len(b), func(i, j int) bool {
+		if i < 0 || i >= len(a) || j < 0 || j >= len(b) {
+			t.Fatal("Equal called out of range", i, j)
+		}
+		return math.Abs(a[i]-b[j]) < 0.001
+	})
+	echange := []diff.Change{{3, 3, 1, 1}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+}
+
+func TestFirstDiff(t *testing.T) {
+	for _, test := range tests {
+		a, b, differ := diff.FirstDiff(len(test.a), len(test.b), &ints{test.a, test.b})
+		res := diff.Ints(test.a, test.b)
+		if differ != (len(res) > 0) {
+			t.Error(test.name, "expected differ", len(res) > 0)
+			continue
+		}
+		if differ && (a != res[0].A || b != res[0].B) {
+			t.Error(test.name, "expected", res[0].A, res[0].B, "got", a, b)
+		}
+	}
+}
+
+func TestIdentical(t *testing.T) {
+	for _, test := range tests {
+		expect := diff.Ints(test.a, test.b) == nil
+		if res := diff.Identical(len(test.a), len(test.b), &ints{test.a, test.b}); res != expect {
+			t.Error(test.name, "expected", expect, "got", res)
+		}
+	}
+	if !diff.Identical(3, 3, &ints{[]int{1, 2, 3}, []int{1, 2, 3}}) {
+		t.Error("expected equal sequences to be identical")
+	}
+	if diff.Identical(3, 4, panicData{}) {
+		t.Error("expected different lengths to differ")
+	}
+}
+
+func TestChangeSetSort(t *testing.T) {
+	changes := diff.ChangeSet{{3, 1, 0, 1}, {1, 2, 1, 0}, {1, 1, 1, 1}, {1, 1, 1, 0}, {0, 5, 2, 2}}
+	sort.Sort(changes)
+	expect := []diff.Change{{0, 5, 2, 2}, {1, 1, 1, 0}, {1, 1, 1, 1}, {1, 2, 1, 0}, {3, 1, 0, 1}}
+	if !diffsEqual(changes, expect) {
+		t.Error("expected", expect, "got", changes)
+	}
+}
+
 func diffsEqual(a, b []diff.Change) bool {
 	if len(a) != len(b) {
 		return false
@@ -130,6 +288,30 @@ func diffsEqual(a, b []diff.Change) bool {
 	return true
 }
 
+// edits returns the number of deleted and inserted elements of changes.
+func edits(changes []diff.Change) (n int) {
+	for _, c := range changes {
+		n += c.Del + c.Ins
+	}
+	return n
+}
+
+// validChanges returns whether changes turn a into b.
+func validChanges(changes []diff.Change, a, b []int) bool {
+	var res []int
+	x := 0
+	for _, c := range changes {
+		if c.A < x {
+			return false
+		}
+		res = append(res, a[x:c.A]...)
+		res = append(res, b[c.B:c.B+c.Ins]...)
+		x = c.A + c.Del
+	}
+	res = append(res, a[x:]...)
+	return slices.Equal(res, b)
+}
+
 func TestGranularStrings(t *testing.T) {
 	a := "abcdefghijklmnopqrstuvwxyza"
 	b := "AbCdeFghiJklmnOpqrstUvwxyzab"
@@ -151,6 +333,79 @@ func TestGranularStrings(t *testing.T) {
 	}
 }
 
+func TestCompact(t *testing.T) {
+	cases := []struct {
+		in, out []diff.Change
+	}{
+		{nil, nil},
+		{[]diff.Change{{0, 0, 0, 0}}, nil},
+		{[]diff.Change{{0, 0, 1, 0}, {1, 0, 0, 2}, {1, 2, 0, 0}, {3, 4, 1, 1}}, []diff.Change{{0, 0, 1, 2}, {3, 4, 1, 1}}},
+		{[]diff.Change{{2, 2, 0, 0}, {2, 2, 1, 1}, {3, 3, 1, 0}, {5, 4, 0, 0}}, []diff.Change{{2, 2, 2, 1}}},
+	}
+	for _, c := range cases {
+		if res := diff.Compact(c.in); !diffsEqual(res, c.out) {
+			t.Error("expected", c.out, "got", res)
+		}
+	}
+}
+
+func TestOnlyInsertsDeletes(t *testing.T) {
+	changes := []diff.Change{{0, 0, 1, 0}, {2, 1, 2, 3}, {6, 6, 0, 1}}
+	echange := []diff.Change{{4, 1, 0, 3}, {6, 6, 0, 1}}
+	if res := diff.OnlyInserts(changes); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	echange = []diff.Change{{0, 0, 1, 0}, {2, 1, 2, 0}}
+	if res := diff.OnlyDeletes(changes); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+}
+
+func TestMapPosition(t *testing.T) {
+	a := "the quick brown fox"
+	b := "a quick red fox!"
+	changes := diff.ByteStrings(a, b)
+	for i := 0; i <= len(a); i++ {
+		j, in := diff.MapPosition(changes, i)
+		if !in && i < len(a) && a[i] != b[j] {
+			t.Errorf("%d maps to %d but %q != %q", i, j, a[i], b[j])
+		}
+		if !in {
+			if k, _ := diff.MapPositionBack(changes, j); k != i {
+				t.Errorf("%d maps to %d and back to %d", i, j, k)
+			}
+		}
+	}
+	for _, c := range changes {
+		for i := c.A; i < c.A+c.Del; i++ {
+			if j, in := diff.MapPosition(changes, i); !in || j != c.B {
+				t.Error("expected position in change at", c.B, "got", j, in)
+			}
+		}
+	}
+	if j, in := diff.MapPosition(changes, len(a)); in || j != len(b) {
+		t.Error("expected end of b got", j, in)
+	}
+}
+
+func TestChangeKind(t *testing.T) {
+	cases := []struct {
+		c    diff.Change
+		kind diff.Kind
+		name string
+	}{
+		{diff.Change{1, 1, 0, 0}, diff.Equal, "equal"},
+		{diff.Change{1, 1, 2, 0}, diff.Delete, "delete"},
+		{diff.Change{1, 1, 0, 2}, diff.Insert, "insert"},
+		{diff.Change{1, 1, 2, 1}, diff.Replace, "replace"},
+	}
+	for _, c := range cases {
+		if k := c.c.Kind(); k != c.kind || k.String() != c.name {
+			t.Error("expected", c.name, "got", k)
+		}
+	}
+}
+
 func TestDiffRunes(t *testing.T) {
 	a := []rune("brown fox jumps over the lazy dog")
 	b := []rune("brwn faax junps ovver the lay dago")
@@ -172,6 +427,45 @@ func TestDiffRunes(t *testing.T) {
 	}
 }
 
+func TestDiffStrings(t *testing.T) {
+	for _, test := range tests {
+		a, b := make([]string, len(test.a)), make([]string, len(test.b))
+		for i, e := range test.a {
+			a[i] = strconv.Itoa(e)
+		}
+		for i, e := range test.b {
+			b[i] = strconv.Itoa(e)
+		}
+		if res := diff.Strings(a, b); !diffsEqual(res, diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "differs from Ints", res)
+		}
+	}
+	// empty and duplicate lines
+	a := []string{"", "x", "x", "", "y"}
+	b := []string{"", "x", "", "", "y", ""}
+	echange := []diff.Change{{2, 2, 1, 1}, {5, 5, 0, 1}}
+	if res := diff.Strings(a, b); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+}
+
+func TestDiffBytes(t *testing.T) {
+	a := []byte{0x00, 0xff, 0x10, 0x20}
+	for _, test := range []struct {
+		name   string
+		b      []byte
+		expect []diff.Change
+	}{
+		{"identical", []byte{0x00, 0xff, 0x10, 0x20}, nil},
+		{"different", []byte{0x01, 0x02, 0x03}, []diff.Change{{0, 0, 4, 3}}},
+		{"empty", nil, []diff.Change{{0, 0, 4, 0}}},
+	} {
+		if res := diff.Bytes(a, test.b); !diffsEqual(res, test.expect) {
+			t.Error(test.name, "expected", test.expect, "got", res)
+		}
+	}
+}
+
 func TestDiffByteStrings(t *testing.T) {
 	a := "brown fox jumps over the lazy dog"
 	b := "brwn faax junps ovver the lay dago"
@@ -214,6 +508,48 @@ func BenchmarkInts(b *testing.B) {
 	}
 }
 
+func BenchmarkDiffSmallEdits(b *testing.B) {
+	d1, d2 := make([]int, 1000), make([]int, 1000)
+	for i := range d1 {
+		d1[i], d2[i] = i, i
+		if i%20 == 10 {
+			d2[i] = -i
+		}
+	}
+	for i := 0; i < b.N; i++ {
+		diff.Ints(d1, d2)
+	}
+}
+
+func mostlyEqual(n int) ([]byte, []byte) {
+	a, b := make([]byte, n), make([]byte, n)
+	for i := range a {
+		a[i] = byte(i)
+		b[i] = byte(i)
+	}
+	b[n/2] = ^b[n/2]
+	return a, b
+}
+
+func BenchmarkBytesMostlyEqual(b *testing.B) {
+	d1, d2 := mostlyEqual(1 << 20)
+	for i := 0; i < b.N; i++ {
+		diff.Bytes(d1, d2)
+	}
+}
+
+func BenchmarkDiffMostlyEqual(b *testing.B) {
+	d1, d2 := mostlyEqual(1 << 20)
+	d := &byteData{d1, d2}
+	for i := 0; i < b.N; i++ {
+		diff.Diff(len(d1), len(d2), d)
+	}
+}
+
+type byteData struct{ a, b []byte }
+
+func (d *byteData) Equal(i, j int) bool { return d.a[i] == d.b[j] }
+
 func BenchmarkDiffRunes(b *testing.B) {
 	d1 := []rune("1231221")
 	d2 := []rune("321213")
@@ -230,6 +566,20 @@ func BenchmarkDiffBytes(b *testing.B) {
 	}
 }
 
+func BenchmarkBytesBinary(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	d1 := make([]byte, 4<<10)
+	r.Read(d1)
+	d2 := append([]byte(nil), d1...)
+	for i := 0; i < len(d2); i += 100 {
+		d2[i] = byte(r.Intn(256))
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		diff.Bytes(d1, d2)
+	}
+}
+
 func BenchmarkDiffByteStrings(b *testing.B) {
 	d1 := "lorem ipsum dolor sit amet consectetur"
 	d2 := "lorem lovesum daenerys targaryen ami consecteture"
@@ -0,0 +1,158 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// A Differ computes differences and keeps its scratch space between calls,
+// so that many diffs in a row do not allocate it again each time.
+// The zero value is ready to use. A Differ must not be used concurrently.
+type Differ struct {
+	// Alloc provides the scratch space for the d-path endpoints if not nil.
+	// It must not be changed while d holds scratch space from another allocator.
+	Alloc Allocator
+	// Boundary reports whether a change may start or end at position i of
+	// input a, for example between logical units of tokens, if not nil.
+	// Changes are widened over the equal elements around them to the next
+	// boundaries and merged where they meet, so units are never split.
+	// The result is still a valid diff but no longer minimal.
+	// Positions 0 and n are always boundaries.
+	Boundary func(i int) bool
+	// Exclude reports whether element a of input a must never match
+	// element b of input b, even if data reports them equal, if not nil.
+	// It applies to every comparison, including the common prefix and
+	// suffix eaten before the search, so an excluded pair is always
+	// deleted and inserted. A Prehashed data is then compared in full.
+	Exclude func(a, b int) bool
+	// AlignRepeats places insertions and deletions within runs of repeated
+	// equal elements, like blank lines, at the start or end of the run.
+	// The default AlignAny keeps the placement found by the algorithm.
+	AlignRepeats AlignPolicy
+	// TrailingWS controls how DiffLines and HTML treat trailing whitespace.
+	TrailingWS TrailingWSMode
+	c          state
+}
+
+// An AlignPolicy places a pure insertion or deletion along the equal
+// elements around it.
+type AlignPolicy int
+
+const (
+	AlignAny   AlignPolicy = iota // keep the placement found by the algorithm
+	AlignFirst                    // move changes as far to the start as possible
+	AlignLast                     // move changes as far to the end as possible
+)
+
+// An Allocator provides scratch space to a Differ, for example from an arena.
+// Alloc returns a slice of length n, its contents need not be zeroed.
+// Free is called with each slice once the Differ no longer uses it.
+type Allocator interface {
+	Alloc(n int) []int
+	Free([]int)
+}
+
+// Diff returns the differences of data like the package function Diff.
+// The scratch space is grown as needed and retained for the next call.
+func (d *Differ) Diff(n, m int, data Data) []Change {
+	// empty inputs need no scratch space
+	switch {
+	case n == 0 && m == 0:
+		return nil
+	case n == 0 || m == 0:
+		return []Change{{0, 0, n, m}}
+	}
+	if d.Exclude != nil {
+		data = &excluded{data, d.Exclude}
+	}
+	if h, ok := data.(Prehashed); ok && n == m {
+		if ha, hb := h.Hashes(); ha == hb {
+			return nil
+		}
+	}
+	c := &d.c
+	size := n
+	if m > size {
+		size = m
+	}
+	if cap(c.flags) < size {
+		c.flags = make([]byte, size)
+	} else {
+		c.flags = c.flags[:size]
+		for i := range c.flags {
+			c.flags[i] = 0
+		}
+	}
+	c.data = data
+	c.alloc = d.Alloc
+	c.max = n + m + 1
+	c.compare(0, 0, n, m)
+	res := c.result(n, m)
+	if d.AlignRepeats != AlignAny {
+		res = alignRepeats(n, data, res, d.AlignRepeats)
+	}
+	c.data = nil
+	if d.Boundary != nil {
+		res = d.widen(n, res)
+	}
+	return res
+}
+
+// excluded wraps data to never match the pairs reported by exclude.
+type excluded struct {
+	data    Data
+	exclude func(a, b int) bool
+}
+
+func (d *excluded) Equal(i, j int) bool {
+	return !d.exclude(i, j) && d.data.Equal(i, j)
+}
+
+// alignRepeats slides the pure insertions and deletions of changes to the
+// start or end of the equal run around them, but not past other changes.
+// Changes that come to touch a neighbor are merged with it.
+func alignRepeats(n int, data Data, changes []Change, policy AlignPolicy) []Change {
+	if policy == AlignFirst {
+		return slideMerge(n, data, changes, false, -1)
+	}
+	return slideMerge(n, data, changes, false, 1)
+}
+
+// widen extends the changes to the boundaries and merges overlapping ones.
+func (d *Differ) widen(n int, changes []Change) []Change {
+	res := changes[:0]
+	for i, c := range changes {
+		prev, next := 0, n
+		if l := len(res); l > 0 {
+			prev = res[l-1].A + res[l-1].Del
+		}
+		if i+1 < len(changes) {
+			next = changes[i+1].A
+		}
+		// the elements around a change are equal in both inputs
+		for c.A > prev && !d.Boundary(c.A) {
+			c = Change{c.A - 1, c.B - 1, c.Del + 1, c.Ins + 1}
+		}
+		for c.A+c.Del < next && !d.Boundary(c.A+c.Del) {
+			c.Del++
+			c.Ins++
+		}
+		if l := len(res); l > 0 && c.A <= prev {
+			p := res[l-1]
+			res[l-1] = Change{p.A, p.B, c.A + c.Del - p.A, c.B + c.Ins - p.B}
+			continue
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+// Release drops the scratch space retained by d.
+// The scratch space is proportional to the largest input diffed so far.
+// Call Release after an unusually large diff if d is kept around for
+// smaller ones, otherwise that memory is held as long as d is.
+// The scratch space from an allocator is freed.
+// d can still be used afterwards.
+func (d *Differ) Release() {
+	d.c.free()
+	d.c = state{}
+}
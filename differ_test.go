@@ -0,0 +1,170 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"math/rand"
+	"testing"
+)
+
+func TestDiffer(t *testing.T) {
+	var d diff.Differ
+	// run all tests twice in both directions to reuse differently sized scratch
+	for i := 0; i < 2; i++ {
+		for _, test := range tests {
+			res := d.Diff(len(test.a), len(test.b), &ints{test.a, test.b})
+			if !diffsEqual(res, diff.Ints(test.a, test.b)) {
+				t.Error(test.name, "differs from Ints", res)
+			}
+			res = d.Diff(len(test.b), len(test.a), &ints{test.b, test.a})
+			if !diffsEqual(res, diff.Ints(test.b, test.a)) {
+				t.Error(test.name, "reversed differs from Ints", res)
+			}
+		}
+		d.Release()
+	}
+}
+
+type countingAlloc struct{ live int }
+
+func (a *countingAlloc) Alloc(n int) []int {
+	a.live++
+	return make([]int, n)
+}
+
+func (a *countingAlloc) Free([]int) { a.live-- }
+
+func TestDifferAlloc(t *testing.T) {
+	alloc := &countingAlloc{}
+	d := diff.Differ{Alloc: alloc}
+	a, b := make([]int, 100), make([]int, 100)
+	for i := range a {
+		a[i], b[i] = i, i
+		if i%10 == 5 {
+			b[i] = -i
+		}
+	}
+	res := d.Diff(len(a), len(b), &ints{a, b})
+	if !diffsEqual(res, diff.Ints(a, b)) {
+		t.Error("differs from Ints", res)
+	}
+	if alloc.live != 2 {
+		t.Error("expected two live slices got", alloc.live)
+	}
+	d.Release()
+	if alloc.live != 0 {
+		t.Error("expected no live slices got", alloc.live)
+	}
+}
+
+func TestDifferBoundary(t *testing.T) {
+	// key value pairs
+	a := []int{1, 10, 2, 20, 3, 30}
+	b := []int{1, 10, 2, 21, 3, 30, 4, 30}
+	d := diff.Differ{Boundary: func(i int) bool { return i%2 == 0 }}
+	res := d.Diff(len(a), len(b), &ints{a, b})
+	echange := []diff.Change{{2, 2, 4, 6}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	d.Boundary = func(i int) bool { return i%3 == 0 }
+	res = d.Diff(len(a), len(b), &ints{a, b})
+	echange = []diff.Change{{3, 3, 3, 5}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	d.Boundary = func(int) bool { return true }
+	for _, test := range tests {
+		res := d.Diff(len(test.a), len(test.b), &ints{test.a, test.b})
+		if !diffsEqual(res, diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "differs from Ints", res)
+		}
+	}
+}
+
+func TestDifferExclude(t *testing.T) {
+	// the volatile 5s coincide but must not match, even in prefix and suffix
+	a := []int{0, 5, 1, 2, 5}
+	b := []int{0, 5, 1, 2, 5}
+	d := diff.Differ{Exclude: func(i, j int) bool { return a[i] == 5 }}
+	res := d.Diff(len(a), len(b), &ints{a, b})
+	echange := []diff.Change{{1, 1, 1, 1}, {4, 4, 1, 1}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	d.Exclude = func(int, int) bool { return false }
+	for _, test := range tests {
+		res := d.Diff(len(test.a), len(test.b), &ints{test.a, test.b})
+		if !diffsEqual(res, diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "differs from Ints", res)
+		}
+	}
+}
+
+func TestDifferAlignRepeats(t *testing.T) {
+	a := []int{1, 2, 2, 2, 3, 4, 4}
+	b := []int{1, 2, 2, 3, 4, 4, 4}
+	var d diff.Differ
+	for _, test := range []struct {
+		policy diff.AlignPolicy
+		expect []diff.Change
+	}{
+		{diff.AlignFirst, []diff.Change{{1, 1, 1, 0}, {5, 4, 0, 1}}},
+		{diff.AlignLast, []diff.Change{{3, 3, 1, 0}, {7, 6, 0, 1}}},
+	} {
+		d.AlignRepeats = test.policy
+		res := d.Diff(len(a), len(b), &ints{a, b})
+		if !diffsEqual(res, test.expect) {
+			t.Error(test.policy, "expected", test.expect, "got", res)
+		}
+		for _, tt := range tests {
+			res := d.Diff(len(tt.a), len(tt.b), &ints{tt.a, tt.b})
+			if edits(res) != edits(diff.Ints(tt.a, tt.b)) || !validChanges(res, tt.a, tt.b) {
+				t.Error(test.policy, tt.name, "invalid or not minimal", res)
+			}
+		}
+	}
+}
+
+// smallPairs returns many small pairs of sequences with a few edits each.
+func smallPairs() [][2][]int {
+	r := rand.New(rand.NewSource(1))
+	res := make([][2][]int, 1000)
+	for k := range res {
+		a, b := make([]int, 20+r.Intn(20)), make([]int, 0, 40)
+		for i := range a {
+			a[i] = r.Intn(10)
+			if r.Intn(5) > 0 {
+				b = append(b, a[i])
+			} else {
+				b = append(b, r.Intn(10))
+			}
+		}
+		res[k] = [2][]int{a, b}
+	}
+	return res
+}
+
+func BenchmarkDiffSmallPairs(b *testing.B) {
+	pairs := smallPairs()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, p := range pairs {
+			diff.Diff(len(p[0]), len(p[1]), &ints{p[0], p[1]})
+		}
+	}
+}
+
+func BenchmarkDifferSmallPairs(b *testing.B) {
+	pairs := smallPairs()
+	var d diff.Differ
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, p := range pairs {
+			d.Diff(len(p[0]), len(p[1]), &ints{p[0], p[1]})
+		}
+	}
+}
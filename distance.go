@@ -0,0 +1,254 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import "unicode/utf8"
+
+// LCSLen returns the length in runes of the longest common subsequence of a and b.
+// It is meant as a cheap similarity score and does not compute any changes.
+// Common prefix and suffix are skipped without allocating, the runes between
+// them and the d-path endpoints share one allocation. Use Differ.LCSLen to
+// reuse it.
+func LCSLen(a, b string) int {
+	common, a, b := trimRunes(a, b)
+	if len(a) == 0 || len(b) == 0 {
+		return common
+	}
+	l := utf8.RuneCountInString(a) + utf8.RuneCountInString(b)
+	buf := make([]int, 3*l+3)
+	return common + lcsRunes(buf[:l], buf[l:], a, b, &ints{})
+}
+
+// LCSLen returns the lcs length of a and b like the package function LCSLen,
+// without allocating once the scratch space of d is large enough.
+// Only the Alloc field of d is used.
+func (d *Differ) LCSLen(a, b string) int {
+	common, a, b := trimRunes(a, b)
+	if len(a) == 0 || len(b) == 0 {
+		return common
+	}
+	l := utf8.RuneCountInString(a) + utf8.RuneCountInString(b)
+	c := &d.c
+	c.alloc = d.Alloc
+	c.grow(2*l + 3)
+	// the reverse endpoints are not needed and hold the runes
+	return common + lcsRunes(c.reverse, c.forward, a, b, &c.runes)
+}
+
+// trimRunes returns the number of runes in the common prefix and suffix of
+// a and b and the strings between them.
+func trimRunes(a, b string) (int, string, string) {
+	common := 0
+	for len(a) > 0 && len(b) > 0 {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			break
+		}
+		a, b = a[na:], b[nb:]
+		common++
+	}
+	for len(a) > 0 && len(b) > 0 {
+		ra, na := utf8.DecodeLastRuneInString(a)
+		rb, nb := utf8.DecodeLastRuneInString(b)
+		if ra != rb {
+			break
+		}
+		a, b = a[:len(a)-na], b[:len(b)-nb]
+		common++
+	}
+	return common, a, b
+}
+
+// lcsRunes decodes the runes of a and b into r for data and returns their lcs
+// length, using v for the d-path endpoints.
+func lcsRunes(r, v []int, a, b string, data *ints) int {
+	data.a = r[:0]
+	for _, x := range a {
+		data.a = append(data.a, int(x))
+	}
+	data.b = r[len(data.a):len(data.a)]
+	for _, x := range b {
+		data.b = append(data.b, int(x))
+	}
+	n, m := len(data.a), len(data.b)
+	return (n + m - distanceIn(v, 0, n, m, data)) / 2
+}
+
+// Distance returns the length of the shortest edit script of data, the
+// number of deleted and inserted elements in the result of Diff. It does not
+// build any changes. The common prefix and suffix are skipped without
+// allocating, only the d-path endpoints of the region between them are.
+// Use Differ.Distance to reuse them.
+func Distance(n, m int, data Data) int {
+	var d Differ
+	return d.Distance(n, m, data)
+}
+
+// Distance returns the edit distance of data like the package function
+// Distance. The d-path endpoints are kept in the scratch space of d, so
+// repeated calls do not allocate once it is large enough. Only the Alloc
+// field of d is used.
+func (d *Differ) Distance(n, m int, data Data) int {
+	a := 0
+	for a < n && a < m && data.Equal(a, a) {
+		a++
+	}
+	for n > a && m > a && data.Equal(n-1, m-1) {
+		n--
+		m--
+	}
+	if a == n || a == m {
+		return n + m - 2*a
+	}
+	c := &d.c
+	c.alloc = d.Alloc
+	c.grow(2*(n+m-2*a) + 3)
+	return distanceIn(c.forward, a, n-a, m-a, data)
+}
+
+// DiffMax returns the differences of data and true if its edit distance is at
+// most maxd, or else nil and false. The middle snake search stops once the
+// distance must exceed maxd, so very different inputs are rejected after
+// O((n+m)*maxd) steps instead of running the full diff.
+func DiffMax(n, m int, data Data, maxd int) ([]Change, bool) {
+	switch {
+	case maxd < 0:
+		return nil, false
+	case maxd == 0:
+		return nil, Identical(n, m, data)
+	}
+	var d Differ
+	// a search at d finds paths of at least 2*d-1 edits
+	d.c.dlimit = (maxd + 1) / 2
+	res := d.Diff(n, m, data)
+	dist := 0
+	for _, c := range res {
+		dist += c.Del + c.Ins
+	}
+	if dist > maxd {
+		return nil, false
+	}
+	return res, true
+}
+
+// CommonCount returns the number of matched element pairs in a longest common
+// subsequence of data, the numerator of similarity metrics like Dice's.
+// It is computed from the edit distance D as (n+m-D)/2 like Distance and
+// allocates like it. Use Differ.CommonCount to avoid that.
+func CommonCount(n, m int, data Data) int {
+	return (n + m - Distance(n, m, data)) / 2
+}
+
+// CommonCount returns the common count of data like the package function
+// CommonCount, without allocating once the scratch space of d is large enough.
+func (d *Differ) CommonCount(n, m int, data Data) int {
+	return (n + m - d.Distance(n, m, data)) / 2
+}
+
+// Ratio returns the similarity of the sequences of data from 0 to 1 as
+// 2*matches/(n+m) like Python's difflib, where matches is CommonCount.
+// Two empty sequences have a similarity of 1.
+func Ratio(n, m int, data Data) float64 {
+	if n+m == 0 {
+		return 1
+	}
+	return float64(2*CommonCount(n, m, data)) / float64(n+m)
+}
+
+// LCS returns the index pairs of a longest common subsequence of data, the
+// elements that are matched by the result of Diff. The pairs are strictly
+// increasing in both indices.
+func LCS(n, m int, data Data) [][2]int {
+	return matches(n, Diff(n, m, data))
+}
+
+// RatioWeighted returns the weighted similarity of the sequences of data.
+// Each element of a and b has the weight returned by wa and wb. The result is
+// the weight of the matched elements of both sequences divided by the weight
+// of all elements, so with weights of 1 it is 2*matches/(n+m). If the total
+// weight is zero the result is 1.
+func RatioWeighted(n, m int, data Data, wa, wb func(i int) float64) float64 {
+	var total, matched float64
+	for i := 0; i < n; i++ {
+		total += wa(i)
+	}
+	for j := 0; j < m; j++ {
+		total += wb(j)
+	}
+	if total == 0 {
+		return 1
+	}
+	for _, p := range matches(n, Diff(n, m, data)) {
+		matched += wa(p[0]) + wb(p[1])
+	}
+	return matched / total
+}
+
+// stringRatio returns the similarity of a and b as 2*LCSLen/(runes of a and b).
+// Two empty strings have a similarity of 1.
+func stringRatio(a, b string) float64 {
+	total := utf8.RuneCountInString(a) + utf8.RuneCountInString(b)
+	if total == 0 {
+		return 1
+	}
+	return float64(2*LCSLen(a, b)) / float64(total)
+}
+
+// distance returns the length of the shortest edit script of data.
+// It uses the greedy forward search and only keeps the d-path endpoints.
+func distance(n, m int, data Data) int {
+	return distanceIn(make([]int, 2*(n+m)+3), 0, n, m, data)
+}
+
+// distanceIn is distance for the region of data starting at a in both
+// inputs, with v of at least 2*(n+m)+3 elements for the d-path endpoints.
+func distanceIn(v []int, a, n, m int, data Data) int {
+	max := n + m
+	off := max + 1
+	v[off+1] = 0
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[off+k-1] < v[off+k+1] {
+				x = v[off+k+1] // down
+			} else {
+				x = v[off+k-1] + 1 // right
+			}
+			y := x - k
+			for x < n && y < m && data.Equal(a+x, a+y) {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				return d
+			}
+		}
+	}
+	return max
+}
+
+// Closest returns the index of the candidate with the smallest edit distance
+// to data and the changes from data to that candidate. The candidates are
+// only scored and the changes are computed for the winner alone. An exact
+// match ends the search early. Without candidates the index is -1.
+func Closest(data []int, candidates [][]int) (index int, changes []Change) {
+	index = -1
+	best := 0
+	for i, c := range candidates {
+		d := distance(len(data), len(c), &ints{data, c})
+		if index < 0 || d < best {
+			index, best = i, d
+		}
+		if d == 0 {
+			break
+		}
+	}
+	if index < 0 {
+		return index, nil
+	}
+	return index, Ints(data, candidates[index])
+}
@@ -0,0 +1,187 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestLCSLen(t *testing.T) {
+	cases := []struct {
+		a, b string
+		n    int
+	}{
+		{"", "", 0},
+		{"abc", "", 0},
+		{"abc", "abc", 3},
+		{"fzf", "fuzzy finder", 3},
+		{"brown fox jumps over the lazy dog", "brwn faax junps ovver the lay dago", 28},
+		{"sögen", "mögen", 4},
+	}
+	for _, c := range cases {
+		if n := diff.LCSLen(c.a, c.b); n != c.n {
+			t.Errorf("LCSLen(%q, %q) expected %d got %d", c.a, c.b, c.n, n)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	for _, test := range tests {
+		expect := edits(diff.Ints(test.a, test.b))
+		if res := diff.Distance(len(test.a), len(test.b), &ints{test.a, test.b}); res != expect {
+			t.Error(test.name, "expected", expect, "got", res)
+		}
+	}
+}
+
+func TestLCS(t *testing.T) {
+	// paper fig. 1
+	a, b := "abcabba", "cbabac"
+	res := diff.LCS(len(a), len(b), &stringData{a, b})
+	if len(res) != 4 {
+		t.Error("expected 4 pairs got", res)
+	}
+	for k, p := range res {
+		if a[p[0]] != b[p[1]] || k > 0 && (p[0] <= res[k-1][0] || p[1] <= res[k-1][1]) {
+			t.Error("invalid pair", p, "in", res)
+		}
+	}
+	for _, test := range tests {
+		dels := 0
+		for _, c := range diff.Ints(test.a, test.b) {
+			dels += c.Del
+		}
+		if res := diff.LCS(len(test.a), len(test.b), &ints{test.a, test.b}); len(res) != len(test.a)-dels {
+			t.Error(test.name, "expected", len(test.a)-dels, "pairs got", res)
+		}
+	}
+}
+
+func TestDiffMax(t *testing.T) {
+	for _, test := range tests {
+		expect := diff.Ints(test.a, test.b)
+		dist := edits(expect)
+		res, ok := diff.DiffMax(len(test.a), len(test.b), &ints{test.a, test.b}, dist)
+		if !ok || !diffsEqual(res, expect) {
+			t.Error(test.name, "expected", expect, "got", res, ok)
+		}
+		if res, ok := diff.DiffMax(len(test.a), len(test.b), &ints{test.a, test.b}, dist-1); ok || res != nil {
+			t.Error(test.name, "expected rejection below", dist, "got", res)
+		}
+	}
+	a, b := make([]int, 10000), make([]int, 10000)
+	for i := range a {
+		a[i], b[i] = i, -i-1
+	}
+	data := &countingInts{ints: ints{a, b}}
+	if _, ok := diff.DiffMax(len(a), len(b), data, 10); ok {
+		t.Error("expected distant inputs to be rejected")
+	}
+	if data.calls > 1000 {
+		t.Error("expected an early stop got", data.calls, "calls")
+	}
+}
+
+func TestCommonCount(t *testing.T) {
+	for _, test := range tests {
+		n, m := len(test.a), len(test.b)
+		expect := (n + m - edits(diff.Ints(test.a, test.b))) / 2
+		if res := diff.CommonCount(n, m, &ints{test.a, test.b}); res != expect {
+			t.Error(test.name, "expected", expect, "got", res)
+		}
+	}
+	data := &ints{[]int{1, 2, 3, 4, 5}, []int{1, 2, 3, 4, 5, 6}}
+	if allocs := testing.AllocsPerRun(10, func() { diff.CommonCount(5, 6, data) }); allocs != 0 {
+		t.Error("expected no allocations for a prefix got", allocs)
+	}
+	var d diff.Differ
+	data = &ints{[]int{1, 2, 3, 4, 5}, []int{5, 4, 3, 2, 1, 6}}
+	if res := d.CommonCount(5, 6, data); res != 1 {
+		t.Error("expected 1 got", res)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { d.CommonCount(5, 6, data) }); allocs != 0 {
+		t.Error("expected no allocations with a Differ got", allocs)
+	}
+}
+
+func TestRatio(t *testing.T) {
+	cases := []struct {
+		a, b  []int
+		ratio float64
+	}{
+		{nil, nil, 1},
+		{[]int{1, 2, 3}, []int{1, 2, 3}, 1},
+		{[]int{1, 2, 3}, []int{4, 5}, 0},
+		{[]int{1, 2, 3, 4}, []int{1, 3, 4, 5, 6, 7}, 0.6},
+	}
+	for _, c := range cases {
+		if r := diff.Ratio(len(c.a), len(c.b), &ints{c.a, c.b}); r != c.ratio {
+			t.Error(c.a, c.b, "expected", c.ratio, "got", r)
+		}
+	}
+}
+
+func TestClosest(t *testing.T) {
+	data := []int{1, 2, 3, 4, 5}
+	candidates := [][]int{
+		{5, 4, 3, 2, 1},
+		{1, 2, 4, 5},
+		{1, 2, 3, 4, 6},
+	}
+	i, changes := diff.Closest(data, candidates)
+	if i != 1 || !diffsEqual(changes, []diff.Change{{2, 2, 1, 0}}) {
+		t.Error("expected 1 got", i, changes)
+	}
+	candidates = append(candidates, data, data)
+	if i, changes := diff.Closest(data, candidates); i != 3 || changes != nil {
+		t.Error("expected 3 got", i, changes)
+	}
+	if i, _ := diff.Closest(data, nil); i != -1 {
+		t.Error("expected -1 got", i)
+	}
+}
+
+func TestRatioWeighted(t *testing.T) {
+	a := []int{1, 2, 3, 4}
+	b := []int{1, 2, 3, 5}
+	one := func(int) float64 { return 1 }
+	if r := diff.RatioWeighted(len(a), len(b), &ints{a, b}, one, one); r != 0.75 {
+		t.Error("expected 0.75 got", r)
+	}
+	// the first element is a heading that weighs as much as the rest
+	heading := func(i int) float64 {
+		if i == 0 {
+			return 3
+		}
+		return 1
+	}
+	if r := diff.RatioWeighted(len(a), len(b), &ints{a, b}, heading, heading); r != 10.0/12 {
+		t.Error("expected 10/12 got", r)
+	}
+	if r := diff.RatioWeighted(0, 0, &ints{}, one, one); r != 1 {
+		t.Error("expected 1 got", r)
+	}
+}
+
+func TestLCSLenAllocs(t *testing.T) {
+	a, b := "lorem ipsum dolor sit amet", "lorem lovesum dolor ami"
+	if allocs := testing.AllocsPerRun(10, func() { diff.LCSLen(a, b) }); allocs > 2 {
+		t.Error("expected at most 2 allocations got", allocs)
+	}
+	var d diff.Differ
+	if res, expect := d.LCSLen(a, b), diff.LCSLen(a, b); res != expect {
+		t.Error("expected", expect, "got", res)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { d.LCSLen(a, b) }); allocs != 0 {
+		t.Error("expected no allocations with a Differ got", allocs)
+	}
+}
+
+func BenchmarkLCSLen(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		diff.LCSLen("lorem ipsum dolor sit amet consectetur", "lorem lovesum daenerys targaryen ami consecteture")
+	}
+}
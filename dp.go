@@ -0,0 +1,54 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// DiffDP returns the differences of data using the classic dynamic programming
+// table of longest common subsequence lengths. It uses O(n*m) time and space
+// and is independent of the O(ND) algorithm used by Diff, which makes it an
+// oracle for testing: both return edit scripts of the same minimal length.
+// For very small inputs it can be faster than Diff.
+func DiffDP(n, m int, data Data) []Change {
+	w := m + 1
+	// lcs[i*w+j] is the length of the lcs of the suffixes at i and j
+	lcs := make([]int, (n+1)*w)
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			k := i*w + j
+			if data.Equal(i, j) {
+				lcs[k] = lcs[k+w+1] + 1
+			} else {
+				lcs[k] = max(lcs[k+w], lcs[k+1])
+			}
+		}
+	}
+	var res []Change
+	add := func(i, j, del, ins int) {
+		if l := len(res) - 1; l >= 0 && res[l].A+res[l].Del == i && res[l].B+res[l].Ins == j {
+			res[l].Del += del
+			res[l].Ins += ins
+			return
+		}
+		res = append(res, Change{i, j, del, ins})
+	}
+	i, j := 0, 0
+	for i < n && j < m {
+		k := i*w + j
+		switch {
+		case lcs[k] == lcs[k+w+1]+1 && data.Equal(i, j):
+			i++
+			j++
+		case lcs[k+w] >= lcs[k+1]:
+			add(i, j, 1, 0)
+			i++
+		default:
+			add(i, j, 0, 1)
+			j++
+		}
+	}
+	if i < n || j < m {
+		add(i, j, n-i, m-j)
+	}
+	return res
+}
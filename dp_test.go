@@ -0,0 +1,43 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"math/rand"
+	"testing"
+)
+
+func TestDiffDP(t *testing.T) {
+	for _, test := range tests {
+		res := diff.DiffDP(len(test.a), len(test.b), &ints{test.a, test.b})
+		if edits(res) != edits(diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "expected minimal edits got", res)
+		}
+	}
+	a := []int{1, 2, 3}
+	b := []int{1, 4, 3, 5}
+	echange := []diff.Change{{1, 1, 1, 1}, {3, 3, 0, 1}}
+	if res := diff.DiffDP(len(a), len(b), &ints{a, b}); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+}
+
+func TestDiffDPOracle(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for k := 0; k < 200; k++ {
+		a, b := make([]int, r.Intn(30)), make([]int, r.Intn(30))
+		for i := range a {
+			a[i] = r.Intn(4)
+		}
+		for i := range b {
+			b[i] = r.Intn(4)
+		}
+		expect := edits(diff.DiffDP(len(a), len(b), &ints{a, b}))
+		if res := edits(diff.Ints(a, b)); res != expect {
+			t.Fatal(a, b, "expected", expect, "edits got", res)
+		}
+	}
+}
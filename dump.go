@@ -0,0 +1,82 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Dump writes a diff case of the inputs a and b and their changes to w,
+// so that it can be attached to a bug report and replayed with Load.
+// The format is line based: a line "a" and a line "b" followed by the
+// elements of the input, and a line "c" with A, B, Del and Ins for each change.
+func Dump(w io.Writer, a, b []int, changes []Change) error {
+	bw := bufio.NewWriter(w)
+	for _, in := range [...]struct {
+		name string
+		s    []int
+	}{{"a", a}, {"b", b}} {
+		bw.WriteString(in.name)
+		for _, v := range in.s {
+			bw.WriteByte(' ')
+			bw.WriteString(strconv.Itoa(v))
+		}
+		bw.WriteByte('\n')
+	}
+	for _, c := range changes {
+		fmt.Fprintf(bw, "c %d %d %d %d\n", c.A, c.B, c.Del, c.Ins)
+	}
+	return bw.Flush()
+}
+
+// Load reads a diff case written by Dump.
+func Load(r io.Reader) (a, b []int, changes []Change, err error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<30)
+	var seen [2]bool
+	for line := 1; sc.Scan(); line++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		vals := make([]int, len(fields)-1)
+		for i, f := range fields[1:] {
+			if vals[i], err = strconv.Atoi(f); err != nil {
+				return nil, nil, nil, fmt.Errorf("diff: load line %d: %v", line, err)
+			}
+		}
+		switch fields[0] {
+		case "a", "b":
+			i := int(fields[0][0] - 'a')
+			if seen[i] {
+				return nil, nil, nil, fmt.Errorf("diff: load line %d: duplicate input %s", line, fields[0])
+			}
+			seen[i] = true
+			if i == 0 {
+				a = vals
+			} else {
+				b = vals
+			}
+		case "c":
+			if len(vals) != 4 {
+				return nil, nil, nil, fmt.Errorf("diff: load line %d: change needs 4 values", line)
+			}
+			changes = append(changes, Change{vals[0], vals[1], vals[2], vals[3]})
+		default:
+			return nil, nil, nil, fmt.Errorf("diff: load line %d: unknown kind %q", line, fields[0])
+		}
+	}
+	if err = sc.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+	if !seen[0] || !seen[1] {
+		return nil, nil, nil, fmt.Errorf("diff: load: missing input")
+	}
+	return a, b, changes, nil
+}
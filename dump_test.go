@@ -0,0 +1,37 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"bytes"
+	"github.com/mb0/diff"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDumpLoad(t *testing.T) {
+	for _, test := range tests {
+		var buf bytes.Buffer
+		changes := diff.Ints(test.a, test.b)
+		if err := diff.Dump(&buf, test.a, test.b, changes); err != nil {
+			t.Fatal(err)
+		}
+		a, b, res, err := diff.Load(&buf)
+		if err != nil {
+			t.Fatal(test.name, err)
+		}
+		if len(a) != len(test.a) || len(a) > 0 && !reflect.DeepEqual(a, test.a) ||
+			len(b) != len(test.b) || len(b) > 0 && !reflect.DeepEqual(b, test.b) ||
+			!diffsEqual(res, changes) {
+			t.Error(test.name, "round trip got", a, b, res)
+		}
+	}
+	for _, bad := range []string{"", "a 1\n", "a 1\nb x\n", "a\nb\nc 1 2\n", "a\na\nb\n", "d 1\n"} {
+		if _, _, _, err := diff.Load(strings.NewReader(bad)); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
@@ -55,3 +55,13 @@ func ExampleGranular() {
 	// Output:
 	// h|ell|o!
 }
+
+func ExampleStrings() {
+	a := []string{"package main", "", "func main() {", "}"}
+	b := []string{"package main", "", "func main() {", "\tprintln()", "}"}
+	for _, c := range diff.Strings(a, b) {
+		fmt.Printf("insert %q at line %d\n", b[c.B:c.B+c.Ins], c.A+1)
+	}
+	// Output:
+	// insert ["\tprintln()"] at line 4
+}
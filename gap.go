@@ -0,0 +1,84 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// DiffGapPenalty returns the differences of data with an affine gap cost
+// instead of the minimal number of edits. A run of k deletions or insertions
+// costs gapOpen+(k-1)*gapExtend, so a gapOpen larger than gapExtend prefers
+// fewer and longer changes over scattered ones.
+// With gapOpen=gapExtend=1 the number of edits is minimal like Diff.
+//
+// It computes the full alignment matrix in O(n*m) time and space and is
+// only suitable for small inputs.
+func DiffGapPenalty(n, m int, data Data, gapOpen, gapExtend int) []Change {
+	if n == 0 || m == 0 {
+		return Diff(n, m, data)
+	}
+	const inf = int(^uint(0) >> 2)
+	w := m + 1
+	// cost of alignments ending with a match, a deletion or an insertion
+	mc, dc, ic := make([]int, (n+1)*w), make([]int, (n+1)*w), make([]int, (n+1)*w)
+	for i := 0; i <= n; i++ {
+		for j := 0; j <= m; j++ {
+			k := i*w + j
+			mc[k], dc[k], ic[k] = inf, inf, inf
+			if i == 0 && j == 0 {
+				mc[k] = 0
+				continue
+			}
+			if i > 0 && j > 0 && data.Equal(i-1, j-1) {
+				p := k - w - 1
+				mc[k] = min(mc[p], dc[p], ic[p])
+			}
+			if i > 0 {
+				p := k - w
+				dc[k] = min(mc[p]+gapOpen, dc[p]+gapExtend, ic[p]+gapOpen)
+			}
+			if j > 0 {
+				p := k - 1
+				ic[k] = min(mc[p]+gapOpen, ic[p]+gapExtend, dc[p]+gapOpen)
+			}
+		}
+	}
+	// trace back and flag the edits like compare does
+	c := &state{flags: make([]byte, max(n, m))}
+	i, j := n, m
+	k := i*w + j
+	mat, cost := 0, mc[k]
+	if dc[k] < cost {
+		mat, cost = 1, dc[k]
+	}
+	if ic[k] < cost {
+		mat = 2
+	}
+	costs := [3][]int{mc, dc, ic}
+	for i > 0 || j > 0 {
+		k = i*w + j
+		want := costs[mat][k]
+		// the cost of entering the matrix from a match, deletion or insertion
+		var p int
+		var enter [3]int
+		switch mat {
+		case 0:
+			p = k - w - 1
+			i, j = i-1, j-1
+		case 1:
+			c.flags[i-1] |= 1
+			p, enter = k-w, [3]int{gapOpen, gapExtend, gapOpen}
+			i--
+		case 2:
+			c.flags[j-1] |= 2
+			p, enter = k-1, [3]int{gapOpen, gapOpen, gapExtend}
+			j--
+		}
+		for s := range costs {
+			if costs[s][p] < inf && costs[s][p]+enter[s] == want {
+				mat = s
+				break
+			}
+		}
+	}
+	return c.result(n, m)
+}
@@ -0,0 +1,31 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestDiffGapPenalty(t *testing.T) {
+	for _, test := range tests {
+		res := diff.DiffGapPenalty(len(test.a), len(test.b), &ints{test.a, test.b}, 1, 1)
+		if edits(res) != edits(diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "expected minimal edits got", res)
+		}
+	}
+	a := []int{1, 2, 3, 4, 5}
+	b := []int{1, 9, 3, 9, 5}
+	res := diff.DiffGapPenalty(len(a), len(b), &ints{a, b}, 1, 1)
+	echange := []diff.Change{{1, 1, 1, 1}, {3, 3, 1, 1}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	res = diff.DiffGapPenalty(len(a), len(b), &ints{a, b}, 10, 1)
+	echange = []diff.Change{{1, 1, 3, 3}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+}
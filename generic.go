@@ -0,0 +1,135 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"math"
+	"slices"
+)
+
+// Slices returns the differences of two slices of comparable elements.
+func Slices[T comparable](a, b []T) []Change {
+	return Diff(len(a), len(b), &comparableSlices[T]{a, b})
+}
+
+type comparableSlices[T comparable] struct{ a, b []T }
+
+func (d *comparableSlices[T]) Equal(i, j int) bool { return d.a[i] == d.b[j] }
+
+// DiffBy returns the differences of a and b compared by the key of each element.
+// Elements with equal keys are aligned even if they differ otherwise, which is
+// what is wanted when diffing records by their primary id. The key is extracted
+// once per element and mapped to an int, so the diff itself only compares ints.
+//
+// The result only reports records that were removed or added. To find records
+// that were kept but modified, compare the values of the aligned elements
+// outside the returned changes in a second pass.
+func DiffBy[T any, K comparable](a, b []T, key func(T) K) []Change {
+	ids := make(map[K]int, len(a))
+	ka := make([]int, len(a))
+	for i, e := range a {
+		ka[i] = keyID(ids, key(e))
+	}
+	kb := make([]int, len(b))
+	for i, e := range b {
+		kb[i] = keyID(ids, key(e))
+	}
+	return Ints(ka, kb)
+}
+
+// DiffMapped returns the differences of a and b after mapping each element with f.
+// f is called once per element, so it is cheaper than comparing with an
+// expensive function, for example to diff paths case-insensitively by mapping
+// them to lower case. It is the same as DiffBy.
+func DiffMapped[T any, U comparable](a, b []T, f func(T) U) []Change {
+	return DiffBy(a, b, f)
+}
+
+func keyID[K comparable](ids map[K]int, k K) int {
+	id, ok := ids[k]
+	if !ok {
+		id = len(ids)
+		ids[k] = id
+	}
+	return id
+}
+
+// DiffNested returns the differences of two sequences of slices, like rows of
+// columns or paths as segments. Two slices are equal if they have the same
+// length and equal elements, as reported by slices.Equal.
+func DiffNested[T comparable](a, b [][]T) []Change {
+	return Diff(len(a), len(b), &eqSlices[[]T]{a, b, slices.Equal[[]T]})
+}
+
+// DiffChanges returns the differences of two lists of changes, for example
+// to see how the output of two diff algorithms or versions differ. Two
+// changes are equal if all four fields are equal.
+func DiffChanges(a, b []Change) []Change {
+	return Diff(len(a), len(b), &eqSlices[Change]{a, b, func(x, y Change) bool { return x == y }})
+}
+
+// DiffCachedHash returns the differences of a and b for elements that are
+// expensive to compare. hash is called once per element and eq is only called
+// for elements with equal hashes. The results of eq are cached without the
+// bound of Memoized, so eq is called at most once for each colliding pair.
+// The cache only grows with the colliding pairs the algorithm compares.
+func DiffCachedHash[T any](a, b []T, hash func(T) uint64, eq func(x, y T) bool) []Change {
+	ha := make([]uint64, len(a))
+	for i, e := range a {
+		ha[i] = hash(e)
+	}
+	hb := make([]uint64, len(b))
+	for i, e := range b {
+		hb[i] = hash(e)
+	}
+	// only colliding pairs reach the cache
+	data := Memoized(&eqSlices[T]{a, b, eq}, math.MaxInt)
+	return Diff(len(a), len(b), &hashedData{ha, hb, data})
+}
+
+type hashedData struct {
+	a, b []uint64
+	data Data
+}
+
+func (d *hashedData) Equal(i, j int) bool { return d.a[i] == d.b[j] && d.data.Equal(i, j) }
+
+// A Pair aligns the element at index A with the one at index B.
+// An index of -1 means the other element has no counterpart.
+type Pair struct {
+	A, B  int
+	Equal bool
+}
+
+// Align returns the dense alignment of a and b using eq to compare elements.
+// Every index of a and b appears exactly once and in order. Equal elements
+// are paired, deleted elements have B -1 and inserted elements have A -1.
+// In a replaced region all deleted elements come before the inserted ones.
+func Align[T any](a, b []T, eq func(x, y T) bool) []Pair {
+	res := make([]Pair, 0, max(len(a), len(b)))
+	x, y := 0, 0
+	for _, c := range Diff(len(a), len(b), &eqSlices[T]{a, b, eq}) {
+		for ; x < c.A; x, y = x+1, y+1 {
+			res = append(res, Pair{x, y, true})
+		}
+		for ; x < c.A+c.Del; x++ {
+			res = append(res, Pair{x, -1, false})
+		}
+		for ; y < c.B+c.Ins; y++ {
+			res = append(res, Pair{-1, y, false})
+		}
+	}
+	for ; x < len(a); x, y = x+1, y+1 {
+		res = append(res, Pair{x, y, true})
+	}
+	return res
+}
+
+type eqSlices[T any] struct {
+	a, b []T
+	eq   func(x, y T) bool
+}
+
+func (d *eqSlices[T]) Equal(i, j int) bool { return d.eq(d.a[i], d.b[j]) }
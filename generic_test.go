@@ -0,0 +1,199 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"math/rand"
+	"testing"
+)
+
+type record struct {
+	ID   int
+	Name string
+}
+
+func TestSlices(t *testing.T) {
+	for _, test := range tests {
+		if res := diff.Slices(test.a, test.b); !diffsEqual(res, diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "differs from Ints", res)
+		}
+	}
+	echange := []diff.Change{{1, 1, 1, 2}}
+	if res := diff.Slices([]string{"a", "b", "c"}, []string{"a", "x", "y", "c"}); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	a := []record{{1, "one"}, {2, "two"}}
+	b := []record{{1, "one"}, {2, "zwei"}}
+	echange = []diff.Change{{1, 1, 1, 1}}
+	if res := diff.Slices(a, b); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	echange = []diff.Change{{0, 0, 0, 2}}
+	if res := diff.Slices(nil, b); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	echange = []diff.Change{{0, 0, 2, 0}}
+	if res := diff.Slices(a, nil); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+}
+
+func TestDiffBy(t *testing.T) {
+	a := []record{{1, "one"}, {2, "two"}, {3, "three"}, {4, "four"}}
+	b := []record{{1, "one"}, {3, "drei"}, {4, "four"}, {5, "five"}}
+	res := diff.DiffBy(a, b, func(r record) int { return r.ID })
+	echange := []diff.Change{{1, 1, 1, 0}, {4, 3, 0, 1}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+}
+
+func TestDiffMapped(t *testing.T) {
+	a := []string{"README", "diff.go", "LICENSE"}
+	b := []string{"readme", "Diff.go", "diff_test.go", "license"}
+	calls := 0
+	lower := func(s string) string {
+		calls++
+		res := []byte(s)
+		for i, c := range res {
+			if c >= 'A' && c <= 'Z' {
+				res[i] = c + 'a' - 'A'
+			}
+		}
+		return string(res)
+	}
+	res := diff.DiffMapped(a, b, lower)
+	echange := []diff.Change{{2, 2, 0, 1}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	if calls != len(a)+len(b) {
+		t.Error("expected one call per element got", calls)
+	}
+}
+
+func TestDiffNested(t *testing.T) {
+	a := [][]string{{"usr", "bin"}, {"usr", "lib"}, {"etc"}}
+	b := [][]string{{"usr", "bin"}, {"usr", "lib", "go"}, {"etc"}, {}}
+	echange := []diff.Change{{1, 1, 1, 1}, {3, 3, 0, 1}}
+	if res := diff.DiffNested(a, b); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+}
+
+func TestDiffChanges(t *testing.T) {
+	a := []diff.Change{{1, 1, 1, 0}, {4, 3, 0, 2}, {9, 10, 1, 1}}
+	b := []diff.Change{{1, 1, 1, 0}, {4, 3, 1, 2}, {9, 10, 1, 1}}
+	echange := []diff.Change{{1, 1, 1, 1}}
+	if res := diff.DiffChanges(a, b); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+}
+
+func TestAlign(t *testing.T) {
+	a := []string{"a", "b", "c", "d"}
+	b := []string{"a", "x", "y", "c", "d", "e"}
+	res := diff.Align(a, b, func(x, y string) bool { return x == y })
+	expect := []diff.Pair{
+		{0, 0, true},
+		{1, -1, false},
+		{-1, 1, false},
+		{-1, 2, false},
+		{2, 3, true},
+		{3, 4, true},
+		{-1, 5, false},
+	}
+	if len(res) != len(expect) {
+		t.Fatal("expected", expect, "got", res)
+	}
+	for i := range res {
+		if res[i] != expect[i] {
+			t.Error("expected", expect[i], "got", res[i])
+		}
+	}
+}
+
+func TestDiffCachedHash(t *testing.T) {
+	// a poor hash makes all even and all odd elements collide
+	hash := func(x int) uint64 { return uint64(x % 2) }
+	for _, test := range tests {
+		res := diff.DiffCachedHash(test.a, test.b, hash, func(x, y int) bool { return x == y })
+		if !diffsEqual(res, diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "differs from Ints", res)
+		}
+	}
+	// enough collisions to exceed the default bound of Memoized
+	type elem struct{ pos, val int }
+	r := rand.New(rand.NewSource(1))
+	a, b := make([]elem, 1000), make([]elem, 1000)
+	for i := range a {
+		a[i], b[i] = elem{i, r.Intn(8)}, elem{i, r.Intn(8)}
+	}
+	seen := make(map[[2]int]bool)
+	calls := 0
+	eq := func(x, y elem) bool {
+		calls++
+		k := [2]int{x.pos, y.pos}
+		if seen[k] {
+			t.Fatal("called eq twice for", k)
+		}
+		seen[k] = true
+		return x.val == y.val
+	}
+	res := diff.DiffCachedHash(a, b, func(elem) uint64 { return 0 }, eq)
+	cached := calls
+	calls = 0
+	clear(seen)
+	naive := diff.DiffFunc(len(a), len(b), func(i, j int) bool {
+		calls++
+		return a[i].val == b[j].val
+	})
+	if !diffsEqual(res, naive) {
+		t.Error("differs from DiffFunc", res)
+	}
+	if cached <= 1<<16 || cached >= calls {
+		t.Error("expected more than", 1<<16, "and fewer than", calls, "calls got", cached)
+	}
+}
+
+func cachedHashInput() ([]record, []record) {
+	a, b := make([]record, 1000), make([]record, 1000)
+	for i := range a {
+		a[i] = record{i, "name"}
+		b[i] = record{i, "name"}
+		if i%20 == 10 {
+			b[i].ID = -i
+		}
+	}
+	return a, b
+}
+
+func BenchmarkDiffCachedHash(b *testing.B) {
+	d1, d2 := cachedHashInput()
+	calls := 0
+	eq := func(x, y record) bool {
+		calls++
+		return x == y
+	}
+	hash := func(r record) uint64 { return uint64(r.ID) }
+	for i := 0; i < b.N; i++ {
+		diff.DiffCachedHash(d1, d2, hash, eq)
+	}
+	b.ReportMetric(float64(calls)/float64(b.N), "eq/op")
+}
+
+func BenchmarkDiffCachedHashNaive(b *testing.B) {
+	d1, d2 := cachedHashInput()
+	calls := 0
+	eq := func(x, y record) bool {
+		calls++
+		return x == y
+	}
+	for i := 0; i < b.N; i++ {
+		diff.Align(d1, d2, eq)
+	}
+	b.ReportMetric(float64(calls)/float64(b.N), "eq/op")
+}
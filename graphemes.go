@@ -0,0 +1,70 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// DiffGraphemes returns the differences of two strings in grapheme clusters.
+// Unlike a rune diff it does not split combining sequences or emoji.
+// The change positions are cluster indices as returned by Graphemes.
+func DiffGraphemes(a, b string) []Change {
+	return DiffGraphemesFunc(a, b, Graphemes)
+}
+
+// DiffGraphemesFunc returns the differences of two strings in the clusters
+// returned by segment. Use it to plug in a complete unicode segmenter.
+func DiffGraphemesFunc(a, b string, segment func(string) []string) []Change {
+	d := &stringSlices{segment(a), segment(b)}
+	return Diff(len(d.a), len(d.b), d)
+}
+
+// Graphemes splits s into approximate grapheme clusters.
+// It is a small segmenter that keeps together CR LF, base runes followed by
+// marks, variation selectors and emoji modifiers, zero width joiner sequences
+// and regional indicator pairs. It is not a full implementation of UAX #29.
+func Graphemes(s string) []string {
+	var res []string
+	for len(s) > 0 {
+		r, n := utf8.DecodeRuneInString(s)
+		joined, regional := false, isRegional(r)
+		for n < len(s) {
+			next, size := utf8.DecodeRuneInString(s[n:])
+			switch {
+			case r == '\r' && next == '\n' && n == 1:
+			case joined:
+				joined = false
+			case next == '\u200d':
+				joined = true
+			case unicode.In(next, unicode.Mn, unicode.Me, unicode.Mc):
+			case next >= 0x1f3fb && next <= 0x1f3ff:
+			case regional && isRegional(next):
+				regional = false
+			default:
+				goto cut
+			}
+			n += size
+		}
+	cut:
+		res = append(res, s[:n])
+		s = s[n:]
+	}
+	return res
+}
+
+func isRegional(r rune) bool { return r >= 0x1f1e6 && r <= 0x1f1ff }
+
+// DiffNormalized returns the rune differences of a and b after normalizing
+// both with normalize, so that canonically equivalent text compares equal.
+// Pass a unicode normalization like norm.NFC.String from the package
+// golang.org/x/text/unicode/norm, which this package does not depend on.
+//
+// The change positions are rune indices of the normalized strings, which can
+// differ from the indices in a and b where the normalization changed the text.
+func DiffNormalized(a, b string, normalize func(string) string) []Change {
+	return Runes([]rune(normalize(a)), []rune(normalize(b)))
+}
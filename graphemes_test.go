@@ -0,0 +1,62 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestGraphemes(t *testing.T) {
+	s := "e\u0301a\r\n\U0001F44D\U0001F3FD\U0001F469\u200d\U0001F4BB\U0001F1E9\U0001F1EA\U0001F1EB\U0001F1F7"
+	expect := []string{
+		"e\u0301", "a", "\r\n",
+		"\U0001F44D\U0001F3FD",
+		"\U0001F469\u200d\U0001F4BB",
+		"\U0001F1E9\U0001F1EA", "\U0001F1EB\U0001F1F7",
+	}
+	res := diff.Graphemes(s)
+	if len(res) != len(expect) {
+		t.Fatalf("expected %q got %q", expect, res)
+	}
+	for i := range res {
+		if res[i] != expect[i] {
+			t.Errorf("expected %q got %q", expect[i], res[i])
+		}
+	}
+}
+
+func TestDiffGraphemes(t *testing.T) {
+	a := "cafe\u0301 \U0001F44D\U0001F3FD"
+	b := "cafe\u0301 \U0001F44D\U0001F3FB"
+	res := diff.DiffGraphemes(a, b)
+	echange := []diff.Change{{5, 5, 1, 1}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+}
+
+func TestDiffNormalized(t *testing.T) {
+	// a toy composition of e and combining acute accent
+	nfc := func(s string) string {
+		var res []rune
+		for _, r := range s {
+			if r == '\u0301' && len(res) > 0 && res[len(res)-1] == 'e' {
+				res[len(res)-1] = '\u00e9'
+				continue
+			}
+			res = append(res, r)
+		}
+		return string(res)
+	}
+	a, b := "cafe\u0301 noir", "caf\u00e9 au lait"
+	if res := diff.Runes([]rune(a), []rune(b)); len(res) == 0 || res[0].A != 3 {
+		t.Error("expected change at accent without normalization got", res)
+	}
+	res := diff.DiffNormalized(a, b, nfc)
+	if len(res) == 0 || res[0].A < 5 {
+		t.Error("expected no change before the space got", res)
+	}
+}
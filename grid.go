@@ -0,0 +1,49 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// A RowChange is a deleted, inserted or modified row of a grid.
+type RowChange struct {
+	A, B  int      // row index in a and b, -1 for inserted or deleted rows
+	Cells []Change // the cell changes of a modified row
+}
+
+// DiffGrid returns the differences of two grids of cells, like two tables.
+// The rows are diffed first by comparing all their cells. Replaced rows are
+// then paired in order and the cells of each pair are diffed, the surplus
+// rows of a replacement are reported as deleted or inserted.
+func DiffGrid(a, b [][]string) []RowChange {
+	var res []RowChange
+	for _, c := range Diff(len(a), len(b), &grids{a, b}) {
+		i := 0
+		for ; i < c.Del && i < c.Ins; i++ {
+			ra, rb := a[c.A+i], b[c.B+i]
+			cells := Diff(len(ra), len(rb), &stringSlices{ra, rb})
+			res = append(res, RowChange{c.A + i, c.B + i, cells})
+		}
+		for j := i; j < c.Del; j++ {
+			res = append(res, RowChange{c.A + j, -1, nil})
+		}
+		for j := i; j < c.Ins; j++ {
+			res = append(res, RowChange{-1, c.B + j, nil})
+		}
+	}
+	return res
+}
+
+type grids struct{ a, b [][]string }
+
+func (d *grids) Equal(i, j int) bool {
+	ra, rb := d.a[i], d.b[j]
+	if len(ra) != len(rb) {
+		return false
+	}
+	for k := range ra {
+		if ra[k] != rb[k] {
+			return false
+		}
+	}
+	return true
+}
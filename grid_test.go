@@ -0,0 +1,34 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"reflect"
+	"testing"
+)
+
+func TestDiffGrid(t *testing.T) {
+	a := [][]string{
+		{"id", "name"},
+		{"1", "one"},
+		{"2", "two"},
+		{"3", "three"},
+	}
+	b := [][]string{
+		{"id", "name", "note"},
+		{"1", "one"},
+		{"3", "three"},
+		{"4", "four"},
+	}
+	expect := []diff.RowChange{
+		{0, 0, []diff.Change{{2, 2, 0, 1}}},
+		{2, -1, nil},
+		{-1, 3, nil},
+	}
+	if res := diff.DiffGrid(a, b); !reflect.DeepEqual(res, expect) {
+		t.Errorf("expected %v got %v", expect, res)
+	}
+}
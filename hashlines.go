@@ -0,0 +1,149 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"bufio"
+	"bytes"
+	"hash/fnv"
+	"io"
+)
+
+// DiffLineHashes returns the line differences of a and b for files too large
+// to hold as lines. Both readers are read once and only a 64-bit hash and the
+// offset of each line are kept, about 16 bytes per line. The changes refer to
+// line indices. Lines keep their line endings like with SplitLines.
+//
+// The hash sequences are diffed and the lines they match are then compared by
+// content to detect collisions, in which case all lines with equal hashes are
+// compared by content. That requires the readers to implement io.ReaderAt,
+// like *os.File does, to read the lines again. Otherwise lines with equal
+// hashes are considered equal.
+func DiffLineHashes(a, b io.Reader) ([]Change, error) {
+	ha, err := hashLines(a)
+	if err != nil {
+		return nil, err
+	}
+	hb, err := hashLines(b)
+	if err != nil {
+		return nil, err
+	}
+	n, m := len(ha.sums), len(hb.sums)
+	res := Slices(ha.sums, hb.sums)
+	ra, oka := a.(io.ReaderAt)
+	rb, okb := b.(io.ReaderAt)
+	if !oka || !okb {
+		return res, nil
+	}
+	data := &lineHashes{a: ha, b: hb, ra: ra, rb: rb}
+	if data.verify(n, res) {
+		return res, nil
+	}
+	if data.err == nil {
+		// a collision matched unequal lines
+		res = Diff(n, m, Memoized(data, 0))
+	}
+	if data.err != nil {
+		return nil, data.err
+	}
+	return res, nil
+}
+
+// hashedLines holds the hashes of lines and their offsets followed by the end.
+type hashedLines struct {
+	sums []uint64
+	offs []int64
+}
+
+func hashLines(r io.Reader) (res hashedLines, err error) {
+	br := bufio.NewReader(r)
+	h := fnv.New64a()
+	var off int64
+	res.offs = append(res.offs, 0)
+	for {
+		line, err := br.ReadSlice('\n')
+		h.Write(line)
+		off += int64(len(line))
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if off > res.offs[len(res.offs)-1] {
+			res.sums = append(res.sums, h.Sum64())
+			res.offs = append(res.offs, off)
+			h.Reset()
+		}
+		if err == io.EOF {
+			return res, nil
+		}
+		if err != nil {
+			return res, err
+		}
+	}
+}
+
+// lineHashes compares lines by hash and then by content read from ra and rb
+// into the reused buffers ba and bb.
+// The first read error is kept in err and the lines compare unequal.
+type lineHashes struct {
+	a, b   hashedLines
+	ra, rb io.ReaderAt
+	ba, bb []byte
+	err    error
+}
+
+// verify returns whether the lines matched by changes are equal by content.
+func (d *lineHashes) verify(n int, changes []Change) bool {
+	x, y := 0, 0
+	for i := 0; i <= len(changes); i++ {
+		end := n
+		if i < len(changes) {
+			end = changes[i].A
+		}
+		for ; x < end; x, y = x+1, y+1 {
+			if !d.Equal(x, y) {
+				return false
+			}
+		}
+		if i < len(changes) {
+			x, y = changes[i].A+changes[i].Del, changes[i].B+changes[i].Ins
+		}
+	}
+	return true
+}
+
+func (d *lineHashes) Equal(i, j int) bool {
+	if d.a.sums[i] != d.b.sums[j] || d.err != nil {
+		return false
+	}
+	la, lb := d.a.offs[i+1]-d.a.offs[i], d.b.offs[j+1]-d.b.offs[j]
+	if la != lb {
+		return false
+	}
+	d.ba, d.err = readLine(d.ra, d.ba, d.a.offs[i], la)
+	if d.err != nil {
+		return false
+	}
+	d.bb, d.err = readLine(d.rb, d.bb, d.b.offs[j], lb)
+	if d.err != nil {
+		return false
+	}
+	return bytes.Equal(d.ba, d.bb)
+}
+
+// readLine reads the line of length l at off into buf and returns it.
+func readLine(r io.ReaderAt, buf []byte, off, l int64) ([]byte, error) {
+	if int64(cap(buf)) < l {
+		buf = make([]byte, l)
+	}
+	buf = buf[:l]
+	// a full read may report io.EOF at the end of the input
+	if n, err := r.ReadAt(buf, off); n < len(buf) {
+		if err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+		return buf, err
+	}
+	return buf, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"bytes"
+	"github.com/mb0/diff"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDiffLineHashes(t *testing.T) {
+	a := "one\ntwo\nthree\n" + strings.Repeat("x", 5000) + "\nfive"
+	b := "one\n2\nthree\n" + strings.Repeat("x", 5000) + "\nfive\nsix\n"
+	echange := []diff.Change{{1, 1, 1, 1}, {4, 4, 1, 2}}
+	// strings.Reader implements io.ReaderAt
+	res, err := diff.DiffLineHashes(strings.NewReader(a), strings.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	// only hashes without io.ReaderAt
+	res, err = diff.DiffLineHashes(bytes.NewBufferString(a), bytes.NewBufferString(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	for _, test := range tests {
+		la, lb := intText(test.a), intText(test.b)
+		res, err := diff.DiffLineHashes(strings.NewReader(la), strings.NewReader(lb))
+		if err != nil || !diffsEqual(res, diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "differs from Ints", res, err)
+		}
+	}
+	if _, err := diff.DiffLineHashes(errReader{}, strings.NewReader(b)); err == nil {
+		t.Error("expected read error")
+	}
+	// a full read of the last line may report io.EOF
+	res, err = diff.DiffLineHashes(eofReader{strings.NewReader(a)}, eofReader{strings.NewReader(b)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	res, err = diff.DiffLineHashes(failReader{strings.NewReader(a)}, strings.NewReader(b))
+	if err == nil || res != nil {
+		t.Error("expected only an error got", res, err)
+	}
+}
+
+// eofReader reports io.EOF with reads that end at the end of the input.
+type eofReader struct{ *strings.Reader }
+
+func (r eofReader) ReadAt(p []byte, off int64) (int, error) {
+	n, err := r.Reader.ReadAt(p, off)
+	if err == nil && off+int64(n) == r.Size() {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// failReader fails to read lines again.
+type failReader struct{ *strings.Reader }
+
+func (failReader) ReadAt([]byte, int64) (int, error) { return 0, io.ErrClosedPipe }
+
+func intText(s []int) string {
+	var buf strings.Builder
+	for _, e := range s {
+		buf.WriteString(string(rune('a'+e)) + "\n")
+	}
+	return buf.String()
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, io.ErrUnexpectedEOF }
@@ -0,0 +1,89 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"html"
+	"strings"
+)
+
+// HTML renders the changes of the lines a and b as HTML. Each line is escaped
+// and wrapped in a div of class "eq", "del" or "ins".
+// Within a change that both deletes and inserts lines, the deleted and
+// inserted lines are paired by order, the first deleted with the first
+// inserted line and so on, and the runes that differ within each pair are
+// wrapped in <del> or <ins>. Unpaired lines are rendered whole.
+// Lines may keep their line endings as returned by SplitLines.
+func HTML(a, b []string, changes []Change) string {
+	return htmlChanges(a, b, changes, false)
+}
+
+// HTMLFunc renders the changes of any two sequences of length n and m like
+// HTML. format returns the text of element i of input b if inB is true, or
+// else of input a. It is called once for every element.
+func HTMLFunc(n, m int, changes []Change, format func(i int, inB bool) string) string {
+	return HTML(formatLines(n, false, format), formatLines(m, true, format), changes)
+}
+
+// htmlChanges renders like HTML and adds the class "ws" to the lines of
+// changes of only trailing whitespace if markWS is true.
+func htmlChanges(a, b []string, changes []Change, markWS bool) string {
+	var buf strings.Builder
+	x := 0
+	for _, c := range changes {
+		htmlLines(&buf, "eq", a[x:c.A])
+		ws := ""
+		if markWS && c.TrailingWSOnly(a, b) {
+			ws = " ws"
+		}
+		p := min(c.Del, c.Ins)
+		for i := 0; i < p; i++ {
+			htmlPair(&buf, trimEOL(a[c.A+i]), trimEOL(b[c.B+i]), ws)
+		}
+		htmlLines(&buf, "del", a[c.A+p:c.A+c.Del])
+		htmlLines(&buf, "ins", b[c.B+p:c.B+c.Ins])
+		x = c.A + c.Del
+	}
+	htmlLines(&buf, "eq", a[x:])
+	return buf.String()
+}
+
+func htmlLines(buf *strings.Builder, class string, lines []string) {
+	for _, l := range lines {
+		buf.WriteString(`<div class="` + class + `">`)
+		buf.WriteString(html.EscapeString(trimEOL(l)))
+		buf.WriteString("</div>\n")
+	}
+}
+
+// htmlPair writes the lines a and b and marks their rune differences.
+// The suffix is appended to the class of both lines.
+func htmlPair(buf *strings.Builder, a, b, suffix string) {
+	ra, rb := []rune(a), []rune(b)
+	changes := Runes(ra, rb)
+	htmlMarked(buf, "del", suffix, ra, changes, false)
+	htmlMarked(buf, "ins", suffix, rb, changes, true)
+}
+
+// htmlMarked writes the line l of input a or b and wraps its changed runes in tag.
+func htmlMarked(buf *strings.Builder, tag, suffix string, l []rune, changes []Change, inB bool) {
+	buf.WriteString(`<div class="` + tag + suffix + `">`)
+	x := 0
+	for _, c := range changes {
+		start, n := c.A, c.Del
+		if inB {
+			start, n = c.B, c.Ins
+		}
+		buf.WriteString(html.EscapeString(string(l[x:start])))
+		if n > 0 {
+			buf.WriteString("<" + tag + ">")
+			buf.WriteString(html.EscapeString(string(l[start : start+n])))
+			buf.WriteString("</" + tag + ">")
+		}
+		x = start + n
+	}
+	buf.WriteString(html.EscapeString(string(l[x:])))
+	buf.WriteString("</div>\n")
+}
@@ -0,0 +1,47 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"strconv"
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	a := []string{"a < b\n", "one\n", "two\n", "end\n"}
+	b := []string{"a < b\n", "once\n", "twice\n", "three\n", "end\n"}
+	changes := []diff.Change{{1, 1, 2, 3}}
+	expect := `<div class="eq">a &lt; b</div>
+<div class="del">one</div>
+<div class="ins">on<ins>c</ins>e</div>
+<div class="del">tw<del>o</del></div>
+<div class="ins">tw<ins>ice</ins></div>
+<div class="ins">three</div>
+<div class="eq">end</div>
+`
+	if res := diff.HTML(a, b, changes); res != expect {
+		t.Errorf("expected\n%s got\n%s", expect, res)
+	}
+}
+
+func TestHTMLFunc(t *testing.T) {
+	a := []int{1, 2, 3}
+	b := []int{1, 4, 3}
+	format := func(i int, inB bool) string {
+		if inB {
+			return strconv.Itoa(b[i])
+		}
+		return strconv.Itoa(a[i])
+	}
+	expect := `<div class="eq">1</div>
+<div class="del"><del>2</del></div>
+<div class="ins"><ins>4</ins></div>
+<div class="eq">3</div>
+`
+	if res := diff.HTMLFunc(len(a), len(b), diff.Ints(a, b), format); res != expect {
+		t.Errorf("expected\n%s got\n%s", expect, res)
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import "sort"
+
+// A ChangeIndex answers whether positions of input a were changed in
+// logarithmic time. It is immutable once built and safe for concurrent use.
+type ChangeIndex struct {
+	ranges []Change
+}
+
+// NewChangeIndex returns an index of the deleted and replaced ranges of changes.
+// The changes must be ordered by ascending positions as returned by this package.
+// Pure insertions change no position of input a and are not indexed.
+func NewChangeIndex(changes []Change) *ChangeIndex {
+	ci := &ChangeIndex{}
+	for _, c := range changes {
+		if c.Del > 0 {
+			ci.ranges = append(ci.ranges, c)
+		}
+	}
+	return ci
+}
+
+// IsChanged returns whether position aPos of input a was deleted or replaced.
+func (ci *ChangeIndex) IsChanged(aPos int) bool {
+	i := sort.Search(len(ci.ranges), func(i int) bool {
+		return ci.ranges[i].A+ci.ranges[i].Del > aPos
+	})
+	return i < len(ci.ranges) && ci.ranges[i].A <= aPos
+}
@@ -0,0 +1,23 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestChangeIndex(t *testing.T) {
+	changes := []diff.Change{{1, 1, 2, 0}, {4, 2, 0, 3}, {6, 8, 1, 1}}
+	ci := diff.NewChangeIndex(changes)
+	for i, expect := range []bool{false, true, true, false, false, false, true, false} {
+		if got := ci.IsChanged(i); got != expect {
+			t.Error("position", i, "expected", expect, "got", got)
+		}
+	}
+	if diff.NewChangeIndex(nil).IsChanged(0) {
+		t.Error("empty index reports a change")
+	}
+}
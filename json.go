@@ -0,0 +1,156 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// A JSONChange is a structural difference between two JSON documents.
+type JSONChange struct {
+	Op   string      // one of "add", "remove" or "replace"
+	Path string      // the path of the value like $.items[3].name
+	Old  interface{} // the removed or replaced value
+	New  interface{} // the added or replacing value
+}
+
+// DiffJSON parses two JSON documents and returns their structural differences.
+// Objects are compared by their key sets and common keys are compared recursively.
+// Arrays are diffed as sequences; replaced elements are compared recursively
+// in pairs and any surplus is reported as removed or added.
+// Removed and replaced array elements use their index in a, added ones in b.
+func DiffJSON(a, b []byte) ([]JSONChange, error) {
+	var va, vb interface{}
+	if err := json.Unmarshal(a, &va); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		return nil, err
+	}
+	return diffJSON(nil, "$", va, vb), nil
+}
+
+func diffJSON(res []JSONChange, path string, a, b interface{}) []JSONChange {
+	switch va := a.(type) {
+	case map[string]interface{}:
+		if vb, ok := b.(map[string]interface{}); ok {
+			return diffJSONObject(res, path, va, vb)
+		}
+	case []interface{}:
+		if vb, ok := b.([]interface{}); ok {
+			return diffJSONArray(res, path, va, vb)
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		res = append(res, JSONChange{"replace", path, a, b})
+	}
+	return res
+}
+
+func diffJSONObject(res []JSONChange, path string, a, b map[string]interface{}) []JSONChange {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		va, oka := a[k]
+		vb, okb := b[k]
+		kpath := jsonKeyPath(path, k)
+		switch {
+		case !okb:
+			res = append(res, JSONChange{"remove", kpath, va, nil})
+		case !oka:
+			res = append(res, JSONChange{"add", kpath, nil, vb})
+		default:
+			res = diffJSON(res, kpath, va, vb)
+		}
+	}
+	return res
+}
+
+func diffJSONArray(res []JSONChange, path string, a, b []interface{}) []JSONChange {
+	for _, c := range Diff(len(a), len(b), &jsonValues{a, b}) {
+		i := 0
+		for ; i < c.Del && i < c.Ins; i++ {
+			res = diffJSON(res, jsonIndexPath(path, c.A+i), a[c.A+i], b[c.B+i])
+		}
+		for j := i; j < c.Del; j++ {
+			res = append(res, JSONChange{"remove", jsonIndexPath(path, c.A+j), a[c.A+j], nil})
+		}
+		for j := i; j < c.Ins; j++ {
+			res = append(res, JSONChange{"add", jsonIndexPath(path, c.B+j), nil, b[c.B+j]})
+		}
+	}
+	return res
+}
+
+type jsonValues struct{ a, b []interface{} }
+
+func (d *jsonValues) Equal(i, j int) bool { return reflect.DeepEqual(d.a[i], d.b[j]) }
+
+func jsonIndexPath(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+func jsonKeyPath(path, key string) string {
+	for i, r := range key {
+		if r != '_' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (i == 0 || r < '0' || r > '9') {
+			return path + "[" + strconv.Quote(key) + "]"
+		}
+	}
+	if key == "" {
+		return path + `[""]`
+	}
+	return path + "." + key
+}
+
+// An Operation is a JSON Patch operation as defined by RFC 6902.
+// Value is ignored by remove operations.
+type Operation struct {
+	Op    string      `json:"op"` // one of "add", "remove" or "replace"
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// JSONPatch returns the JSON Patch operations that turn the array a into b.
+// The operations apply in order, so each path is a JSON Pointer into the
+// document as changed by the operations before it, not into a or b.
+// Elements are compared like by DiffJSON, and replaced elements are replaced
+// whole. An error is returned if a value cannot be encoded as JSON.
+func JSONPatch(a, b []interface{}) ([]Operation, error) {
+	var res []Operation
+	for _, c := range Diff(len(a), len(b), &jsonValues{a, b}) {
+		// the document before c.B is already equal to b
+		i := 0
+		for ; i < c.Del && i < c.Ins; i++ {
+			res = append(res, Operation{"replace", jsonPointer(c.B + i), b[c.B+i]})
+		}
+		for j := i; j < c.Del; j++ {
+			res = append(res, Operation{"remove", jsonPointer(c.B + i), nil})
+		}
+		for j := i; j < c.Ins; j++ {
+			res = append(res, Operation{"add", jsonPointer(c.B + j), b[c.B+j]})
+		}
+	}
+	for _, op := range res {
+		if _, err := json.Marshal(op.Value); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func jsonPointer(i int) string {
+	return "/" + strconv.Itoa(i)
+}
@@ -0,0 +1,71 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestDiffJSON(t *testing.T) {
+	a := `{"name": "a", "old": 1, "items": [{"name": "x"}, {"name": "y"}, 3], "odd key": true}`
+	b := `{"name": "b", "new": null, "items": [{"name": "x"}, {"name": "z"}, 3, 4], "odd key": true}`
+	res, err := diff.DiffJSON([]byte(a), []byte(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []diff.JSONChange{
+		{"replace", "$.items[1].name", "y", "z"},
+		{"add", "$.items[3]", nil, 4.0},
+		{"replace", "$.name", "a", "b"},
+		{"add", "$.new", nil, nil},
+		{"remove", "$.old", 1.0, nil},
+	}
+	if !reflect.DeepEqual(res, expect) {
+		t.Errorf("expected %v got %v", expect, res)
+	}
+	if _, err := diff.DiffJSON([]byte(a), []byte("{")); err == nil {
+		t.Error("expected error for invalid json")
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	a := []interface{}{"a", "b", "c", "d", "e"}
+	b := []interface{}{"a", "x", "d", "e", "f", "g"}
+	res, err := diff.JSONPatch(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []diff.Operation{
+		{"replace", "/1", "x"},
+		{"remove", "/2", nil},
+		{"add", "/4", "f"},
+		{"add", "/5", "g"},
+	}
+	if !reflect.DeepEqual(res, expect) {
+		t.Errorf("expected %v got %v", expect, res)
+	}
+	// apply the operations in order
+	doc := append([]interface{}(nil), a...)
+	for _, op := range res {
+		i, _ := strconv.Atoi(op.Path[1:])
+		switch op.Op {
+		case "replace":
+			doc[i] = op.Value
+		case "remove":
+			doc = append(doc[:i], doc[i+1:]...)
+		case "add":
+			doc = append(doc[:i], append([]interface{}{op.Value}, doc[i:]...)...)
+		}
+	}
+	if !reflect.DeepEqual(doc, b) {
+		t.Error("expected", b, "got", doc)
+	}
+	if _, err := diff.JSONPatch(nil, []interface{}{func() {}}); err == nil {
+		t.Error("expected error for value that cannot be encoded")
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// Lazy produces the elements of a sequence on demand, like rows from a cursor.
+// Next returns the next element and true, or false if the sequence is done.
+type Lazy[T any] interface {
+	Next() (T, bool)
+}
+
+// DiffLazy returns the differences of a and the sequence produced by b,
+// using eq to compare elements.
+//
+// Elements of b that match the common prefix with a are compared as they are
+// produced and not kept. The diff algorithm needs random access and the length
+// of both sides, so starting with the first differing element the remainder of
+// b is buffered in full. Memory therefore grows with the part of b after the
+// first difference, and stays small if b only appends to a.
+func DiffLazy[T any](a []T, b Lazy[T], eq func(x, y T) bool) []Change {
+	var rest []T
+	off := 0
+	for {
+		e, ok := b.Next()
+		if !ok {
+			break
+		}
+		if rest == nil && off < len(a) && eq(a[off], e) {
+			off++
+			continue
+		}
+		rest = append(rest, e)
+	}
+	d := &eqSlices[T]{a[off:], rest, eq}
+	changes := Diff(len(d.a), len(d.b), d)
+	for i := range changes {
+		changes[i].A += off
+		changes[i].B += off
+	}
+	return changes
+}
@@ -0,0 +1,32 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+type cursor []int
+
+func (c *cursor) Next() (int, bool) {
+	if len(*c) == 0 {
+		return 0, false
+	}
+	e := (*c)[0]
+	*c = (*c)[1:]
+	return e, true
+}
+
+func TestDiffLazy(t *testing.T) {
+	eq := func(x, y int) bool { return x == y }
+	for _, test := range tests {
+		b := cursor(test.b)
+		res := diff.DiffLazy(test.a, &b, eq)
+		if !diffsEqual(res, diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "lazy differs from Ints", res)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// DiffLinear returns the same differences of data as Diff, for inputs too
+// large to allocate the d-path endpoints of Diff, which need 2*(n+m+1)
+// elements each in the forward and reverse direction.
+// It uses the same middle snake recursion in O((n+m)*D) time, but keeps the
+// endpoints centered on the diagonals searched so far and grows them with the
+// edit distance D. Similar inputs, like successive versions of a log file,
+// then need O(D) scratch space besides one byte per element of the longer input.
+func DiffLinear(n, m int, data Data) []Change {
+	var d Differ
+	d.c.centered = true
+	return d.Diff(n, m, data)
+}
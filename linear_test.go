@@ -0,0 +1,65 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"math/rand"
+	"testing"
+)
+
+func TestDiffLinear(t *testing.T) {
+	for _, test := range tests {
+		res := diff.DiffLinear(len(test.a), len(test.b), &ints{test.a, test.b})
+		if expect := diff.Ints(test.a, test.b); !diffsEqual(res, expect) {
+			t.Error(test.name, "expected", expect, "got", res)
+		}
+	}
+	r := rand.New(rand.NewSource(1))
+	for k := 0; k < 200; k++ {
+		// long enough to grow the d-path slices
+		a, b := make([]int, r.Intn(400)), make([]int, r.Intn(400))
+		for i := range a {
+			a[i] = r.Intn(4)
+		}
+		for i := range b {
+			b[i] = r.Intn(4)
+		}
+		res := diff.DiffLinear(len(a), len(b), &ints{a, b})
+		if expect := diff.Diff(len(a), len(b), &ints{a, b}); !diffsEqual(res, expect) {
+			t.Fatal(a, b, "expected", expect, "got", res)
+		}
+	}
+}
+
+// linearInput returns a long log with a few edits.
+func linearInput() ([]int, []int) {
+	r := rand.New(rand.NewSource(1))
+	a := make([]int, 1e5)
+	for i := range a {
+		a[i] = r.Int()
+	}
+	b := append([]int(nil), a...)
+	for i := 0; i < 10; i++ {
+		b[r.Intn(len(b))] = -1
+	}
+	return a, append(b, 1, 2, 3)
+}
+
+func BenchmarkDiffLinear(b *testing.B) {
+	d1, d2 := linearInput()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		diff.DiffLinear(len(d1), len(d2), &ints{d1, d2})
+	}
+}
+
+func BenchmarkDiffLinearMyers(b *testing.B) {
+	d1, d2 := linearInput()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		diff.Diff(len(d1), len(d2), &ints{d1, d2})
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+)
+
+// SplitLines splits s after each newline and keeps the line endings.
+// Only the last line can lack a newline, which is how a text without a final
+// newline is told apart from one with it. Formatters use this to emit the
+// "\ No newline at end of file" marker. An empty string returns no lines.
+func SplitLines(s string) []string {
+	var lines []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\n' {
+			lines = append(lines, s[start:i+1])
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		lines = append(lines, s[start:])
+	}
+	return lines
+}
+
+// NoNewline is the marker line following a line that lacks a final newline.
+const NoNewline = "\\ No newline at end of file\n"
+
+// DiffLinesFuzzy returns the differences of the lines a and b where two lines
+// are equal if their rune similarity 2*LCSLen/(runes of both) is at least
+// threshold. A small edit within a line then does not break the alignment.
+// Each comparison diffs two lines, so this is much more expensive than an
+// exact line diff. The comparisons are memoized like with Memoized.
+func DiffLinesFuzzy(a, b []string, threshold float64) []Change {
+	return Diff(len(a), len(b), Memoized(&fuzzyLines{a, b, threshold}, 0))
+}
+
+type fuzzyLines struct {
+	a, b      []string
+	threshold float64
+}
+
+func (d *fuzzyLines) Equal(i, j int) bool {
+	return d.a[i] == d.b[j] || stringRatio(d.a[i], d.b[j]) >= d.threshold
+}
+
+// DiffUnordered compares a and b as multisets, ignoring their order, like
+// import lists or unordered config sections. It returns the elements of b
+// without counterpart in a and the elements of a without counterpart in b,
+// in their order of appearance. A duplicate element counts once per occurrence.
+func DiffUnordered(a, b []string) (added, removed []string) {
+	count := make(map[string]int, len(a))
+	for _, s := range a {
+		count[s]++
+	}
+	for _, s := range b {
+		if count[s] > 0 {
+			count[s]--
+		} else {
+			added = append(added, s)
+		}
+	}
+	// the remaining counts are the removed occurrences
+	for _, s := range a {
+		if count[s] > 0 {
+			count[s]--
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+// MatchedLines returns the index pairs of the equal lines aligned by the diff
+// of a and b, for example to scroll two panes in sync.
+// The pairs are strictly increasing in both indices.
+func MatchedLines(a, b []string) [][2]int {
+	return matches(len(a), Diff(len(a), len(b), &stringSlices{a, b}))
+}
+
+// matches returns the index pairs outside of changes for an input a of length n.
+func matches(n int, changes []Change) [][2]int {
+	var res [][2]int
+	x, y := 0, 0
+	for _, c := range changes {
+		for ; x < c.A; x, y = x+1, y+1 {
+			res = append(res, [2]int{x, y})
+		}
+		x, y = c.A+c.Del, c.B+c.Ins
+	}
+	for ; x < n; x, y = x+1, y+1 {
+		res = append(res, [2]int{x, y})
+	}
+	return res
+}
+
+// ChangedLines returns the ascending indices of the lines of input a that were
+// deleted or replaced by changes.
+func ChangedLines(changes []Change) []int {
+	var res []int
+	for _, c := range changes {
+		for i := c.A; i < c.A+c.Del; i++ {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// InsertedLines returns the ascending indices of the lines of input b that were
+// inserted or are replacements by changes.
+func InsertedLines(changes []Change) []int {
+	var res []int
+	for _, c := range changes {
+		for i := c.B; i < c.B+c.Ins; i++ {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// LineDelta returns the number of lines added and removed by the diff of
+// the lines a and b, as shown in a summary like "+12 -3". It only computes
+// the edit distance like Distance, so a small edit of large inputs allocates
+// little, and does not build the changes. The added minus the
+// removed lines are the difference in length, which splits the distance.
+func LineDelta(a, b []string) (added, removed int) {
+	n, m := len(a), len(b)
+	d := Distance(n, m, &stringSlices{a, b})
+	return (d + m - n) / 2, (d - m + n) / 2
+}
+
+// Key returns a stable key of the position and content of c in the lines a and b.
+// Equal changes of equal content have the same key, so it can be used to
+// index or deduplicate changes across diffs. It is a hex encoded SHA-256 hash.
+func (c Change) Key(a, b []string) string {
+	h := sha256.New()
+	var buf [binary.MaxVarintLen64]byte
+	write := func(n int) {
+		h.Write(buf[:binary.PutUvarint(buf[:], uint64(n))])
+	}
+	for _, n := range [...]int{c.A, c.B, c.Del, c.Ins} {
+		write(n)
+	}
+	// length prefixed lines keep the encoding unambiguous
+	for _, l := range a[c.A : c.A+c.Del] {
+		write(len(l))
+		h.Write([]byte(l))
+	}
+	for _, l := range b[c.B : c.B+c.Ins] {
+		write(len(l))
+		h.Write([]byte(l))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,139 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"reflect"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func TestSplitLines(t *testing.T) {
+	cases := []struct {
+		s     string
+		lines []string
+	}{
+		{"", nil},
+		{"\n", []string{"\n"}},
+		{"a", []string{"a"}},
+		{"a\nb\n", []string{"a\n", "b\n"}},
+		{"a\n\nb", []string{"a\n", "\n", "b"}},
+	}
+	for _, c := range cases {
+		lines := diff.SplitLines(c.s)
+		if len(lines) != len(c.lines) {
+			t.Errorf("%q expected %q got %q", c.s, c.lines, lines)
+			continue
+		}
+		for i := range lines {
+			if lines[i] != c.lines[i] {
+				t.Errorf("%q expected %q got %q", c.s, c.lines, lines)
+			}
+		}
+	}
+}
+
+func TestMatchedLines(t *testing.T) {
+	a := []string{"a", "b", "c", "", "d"}
+	b := []string{"x", "a", "c", "", "", "d"}
+	expect := [][2]int{{0, 1}, {2, 2}, {3, 4}, {4, 5}}
+	res := diff.MatchedLines(a, b)
+	if len(res) != len(expect) {
+		t.Fatal("expected", expect, "got", res)
+	}
+	for i := range res {
+		if res[i] != expect[i] {
+			t.Error("expected", expect[i], "got", res[i])
+		}
+	}
+}
+
+func TestChangedLines(t *testing.T) {
+	changes := []diff.Change{{0, 0, 1, 1}, {2, 2, 1, 0}, {5, 4, 0, 2}, {7, 8, 2, 1}}
+	if res, expect := diff.ChangedLines(changes), []int{0, 2, 7, 8}; !reflect.DeepEqual(res, expect) {
+		t.Error("expected", expect, "got", res)
+	}
+	if res, expect := diff.InsertedLines(changes), []int{0, 4, 5, 8}; !reflect.DeepEqual(res, expect) {
+		t.Error("expected", expect, "got", res)
+	}
+}
+
+func TestLineDelta(t *testing.T) {
+	a := []string{"a", "b", "c", "d"}
+	b := []string{"a", "x", "c", "d", "e", "f"}
+	if added, removed := diff.LineDelta(a, b); added != 3 || removed != 1 {
+		t.Error("expected +3 -1 got", added, removed)
+	}
+	if added, removed := diff.LineDelta(nil, a); added != 4 || removed != 0 {
+		t.Error("expected +4 -0 got", added, removed)
+	}
+	// a small edit of a large file only allocates for the edited region
+	a, b = make([]string, 10000), make([]string, 10000)
+	for i := range a {
+		a[i] = strconv.Itoa(i)
+		b[i] = a[i]
+	}
+	b[5000] = "x"
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	added, removed := diff.LineDelta(a, b)
+	runtime.ReadMemStats(&after)
+	if added != 1 || removed != 1 {
+		t.Error("expected +1 -1 got", added, removed)
+	}
+	if bytes := after.TotalAlloc - before.TotalAlloc; bytes > 1024 {
+		t.Error("expected at most 1024 bytes allocated got", bytes)
+	}
+}
+
+func TestChangeKey(t *testing.T) {
+	a := []string{"a", "b", "c"}
+	b := []string{"a", "bc", "c"}
+	c := diff.Change{1, 1, 1, 1}
+	key := c.Key(a, b)
+	if len(key) != 64 {
+		t.Error("expected hex sha256 got", key)
+	}
+	if other := c.Key([]string{"x", "b"}, []string{"y", "bc"}); other != key {
+		t.Error("expected same key for same content", other)
+	}
+	// content split differently must not collide
+	if other := c.Key([]string{"a", "bb", "c"}, []string{"a", "c", "c"}); other == key {
+		t.Error("expected different key for different content")
+	}
+	if other := (diff.Change{1, 1, 2, 1}).Key(a, b); other == key {
+		t.Error("expected different key for different change")
+	}
+}
+
+func TestDiffLinesFuzzy(t *testing.T) {
+	a := []string{"func main() {", "\tfmt.Println(\"helo\")", "}"}
+	b := []string{"func main() {", "\tfmt.Println(\"hello\")", "}", ""}
+	echange := []diff.Change{{3, 3, 0, 1}}
+	if res := diff.DiffLinesFuzzy(a, b, 0.9); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	echange = []diff.Change{{1, 1, 1, 1}, {3, 3, 0, 1}}
+	if res := diff.DiffLinesFuzzy(a, b, 1); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+}
+
+func TestDiffUnordered(t *testing.T) {
+	a := []string{"fmt", "os", "io", "os", "sort"}
+	b := []string{"sort", "io", "strings", "fmt", "strings"}
+	added, removed := diff.DiffUnordered(a, b)
+	if expect := []string{"strings", "strings"}; !reflect.DeepEqual(added, expect) {
+		t.Error("expected", expect, "got", added)
+	}
+	if expect := []string{"os", "os"}; !reflect.DeepEqual(removed, expect) {
+		t.Error("expected", expect, "got", removed)
+	}
+	if added, removed := diff.DiffUnordered(b, b); added != nil || removed != nil {
+		t.Error("expected nothing got", added, removed)
+	}
+}
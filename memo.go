@@ -0,0 +1,37 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// Memoized returns data wrapped with a cache of the results of data.Equal.
+// Use it when comparing elements is expensive, because the middle snake
+// search compares some pairs more than once.
+//
+// Without a bound the cache could grow up to n*m entries. It holds at most
+// limit results and is cleared when full; a limit of 0 or less uses 1<<16.
+func Memoized(data Data, limit int) Data {
+	if limit <= 0 {
+		limit = 1 << 16
+	}
+	return &memoized{data, limit, make(map[[2]int]bool)}
+}
+
+type memoized struct {
+	data  Data
+	limit int
+	cache map[[2]int]bool
+}
+
+func (d *memoized) Equal(i, j int) bool {
+	k := [2]int{i, j}
+	eq, ok := d.cache[k]
+	if !ok {
+		if len(d.cache) >= d.limit {
+			d.cache = make(map[[2]int]bool)
+		}
+		eq = d.data.Equal(i, j)
+		d.cache[k] = eq
+	}
+	return eq
+}
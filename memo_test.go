@@ -0,0 +1,37 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+type countingInts struct {
+	ints
+	calls int
+}
+
+func (d *countingInts) Equal(i, j int) bool {
+	d.calls++
+	return d.ints.Equal(i, j)
+}
+
+func TestMemoized(t *testing.T) {
+	for _, limit := range []int{0, 3} {
+		for _, test := range tests {
+			data := &countingInts{ints: ints{test.a, test.b}}
+			res := diff.Diff(len(test.a), len(test.b), diff.Memoized(data, limit))
+			if !diffsEqual(res, diff.Ints(test.a, test.b)) {
+				t.Error(test.name, "memoized differs from Ints", res)
+			}
+			direct := &countingInts{ints: ints{test.a, test.b}}
+			diff.Diff(len(test.a), len(test.b), direct)
+			if data.calls > direct.calls {
+				t.Error(test.name, "memoized called Equal more often", data.calls, direct.calls)
+			}
+		}
+	}
+}
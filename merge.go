@@ -0,0 +1,100 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrConflict is returned by merges that found conflicting changes.
+var ErrConflict = errors.New("diff: merge conflict")
+
+// A Conflict is a region of base that was changed differently by more than one variant.
+type Conflict struct {
+	A, Del   int      // the region of base
+	Variants []int    // the indices of the conflicting variants in ascending order
+	Ranges   [][2]int // the start and end of the region in each of the variants
+}
+
+// Merge is the three-way merge of the edits of base in ours and theirs.
+// It is MergeN with two variants, so Ranges of a Conflict holds the region
+// in ours first and in theirs second.
+func Merge(base, ours, theirs []string) ([]string, []Conflict, error) {
+	return MergeN(base, [][]string{ours, theirs})
+}
+
+// MergeN merges the independent edits of base in variants.
+// Each variant is diffed against base. Changes of different variants that
+// overlap or touch in base are merged if all of them replace the region with
+// the same lines, otherwise they are a conflict and the region keeps the lines
+// of base. The merged lines are always returned; if there are conflicts they
+// are returned along with ErrConflict.
+func MergeN(base []string, variants [][]string) ([]string, []Conflict, error) {
+	type edit struct {
+		Change
+		v int
+	}
+	var edits []edit
+	for v, variant := range variants {
+		for _, c := range Diff(len(base), len(variant), &stringSlices{base, variant}) {
+			edits = append(edits, edit{c, v})
+		}
+	}
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].A < edits[j].A })
+	var res []string
+	var conflicts []Conflict
+	x := 0
+	for i := 0; i < len(edits); {
+		s, e := edits[i].A, edits[i].A+edits[i].Del
+		j := i + 1
+		for ; j < len(edits) && edits[j].A <= e; j++ {
+			e = max(e, edits[j].A+edits[j].Del)
+		}
+		// the range of the region in each variant involved
+		var vars []int
+		var ranges [][2]int
+		for _, ed := range edits[i:j] {
+			k := sort.SearchInts(vars, ed.v)
+			if k == len(vars) || vars[k] != ed.v {
+				vars = append(vars, 0)
+				copy(vars[k+1:], vars[k:])
+				vars[k] = ed.v
+				ranges = append(ranges, [2]int{})
+				copy(ranges[k+1:], ranges[k:])
+				ranges[k][0] = ed.B - (ed.A - s)
+			}
+			ranges[k][1] = ed.B + ed.Ins + e - (ed.A + ed.Del)
+		}
+		res = append(res, base[x:s]...)
+		repl := variants[vars[0]][ranges[0][0]:ranges[0][1]]
+		for k := 1; k < len(vars); k++ {
+			if !stringsEqual(repl, variants[vars[k]][ranges[k][0]:ranges[k][1]]) {
+				conflicts = append(conflicts, Conflict{s, e - s, vars, ranges})
+				repl = base[s:e]
+				break
+			}
+		}
+		res = append(res, repl...)
+		x, i = e, j
+	}
+	res = append(res, base[x:]...)
+	if len(conflicts) > 0 {
+		return res, conflicts, ErrConflict
+	}
+	return res, nil, nil
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
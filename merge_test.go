@@ -0,0 +1,74 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	base := []string{"a", "b", "c", "d"}
+	for _, test := range []struct {
+		name         string
+		ours, theirs []string
+		expect       []string
+		conflicts    []diff.Conflict
+	}{
+		{"disjoint",
+			[]string{"A", "b", "c", "d"},
+			[]string{"a", "b", "c", "D", "e"},
+			[]string{"A", "b", "c", "D", "e"}, nil},
+		{"identical",
+			[]string{"a", "x", "c", "d"},
+			[]string{"a", "x", "c", "d"},
+			[]string{"a", "x", "c", "d"}, nil},
+		{"overlapping",
+			[]string{"a", "x", "c", "d"},
+			[]string{"a", "y", "z", "d"},
+			[]string{"a", "b", "c", "d"},
+			[]diff.Conflict{{1, 2, []int{0, 1}, [][2]int{{1, 3}, {1, 3}}}}},
+	} {
+		res, conflicts, err := diff.Merge(base, test.ours, test.theirs)
+		if (err == diff.ErrConflict) != (test.conflicts != nil) {
+			t.Error(test.name, "unexpected error", err)
+		}
+		if !reflect.DeepEqual(res, test.expect) || !reflect.DeepEqual(conflicts, test.conflicts) {
+			t.Error(test.name, "expected", test.expect, test.conflicts, "got", res, conflicts)
+		}
+	}
+}
+
+func TestMergeN(t *testing.T) {
+	base := []string{"a", "b", "c", "d", "e", "f"}
+	variants := [][]string{
+		{"A", "b", "c", "d", "e", "f"},
+		{"a", "b", "C", "d", "e", "f"},
+		{"a", "b", "C", "d", "e", "f", "g"},
+		{"a", "b", "c", "d", "f"},
+	}
+	res, conflicts, err := diff.MergeN(base, variants)
+	if err != nil {
+		t.Fatal(err, conflicts)
+	}
+	expect := []string{"A", "b", "C", "d", "f", "g"}
+	if !reflect.DeepEqual(res, expect) {
+		t.Error("expected", expect, "got", res)
+	}
+	variants = append(variants, []string{"a", "b", "X", "d", "e", "f"})
+	res, conflicts, err = diff.MergeN(base, variants)
+	if err != diff.ErrConflict {
+		t.Fatal("expected conflict got", err)
+	}
+	expect = []string{"A", "b", "c", "d", "f", "g"}
+	if !reflect.DeepEqual(res, expect) {
+		t.Error("expected", expect, "got", res)
+	}
+	econflicts := []diff.Conflict{{2, 1, []int{1, 2, 4}, [][2]int{{2, 3}, {2, 3}, {2, 3}}}}
+	if !reflect.DeepEqual(conflicts, econflicts) {
+		t.Error("expected", econflicts, "got", conflicts)
+	}
+}
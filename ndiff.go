@@ -0,0 +1,92 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import "strings"
+
+// NDiff returns the differences of the lines a and b in the style of Python's
+// difflib.ndiff. Lines are prefixed with "  ", "- " or "+ ". Within replaced
+// lines the most similar pair is found first and the lines before and after
+// it are paired the same way. Each pair is followed by "? " guide lines that
+// mark deleted runes with '-', inserted ones with '+' and replaced ones with '^'.
+// Lines are only paired if at least 3/4 of their runes are common.
+// Lines may keep their line endings as returned by SplitLines.
+func NDiff(a, b []string) string {
+	var buf strings.Builder
+	x := 0
+	for _, c := range Diff(len(a), len(b), &stringSlices{a, b}) {
+		ndiffLines(&buf, "  ", a[x:c.A])
+		ndiffReplace(&buf, a[c.A:c.A+c.Del], b[c.B:c.B+c.Ins])
+		x = c.A + c.Del
+	}
+	ndiffLines(&buf, "  ", a[x:])
+	return buf.String()
+}
+
+// ndiffReplace writes the most similar pair of lines with guides and
+// recurses on the lines before and after it.
+func ndiffReplace(buf *strings.Builder, a, b []string) {
+	bi, bj, best := -1, -1, 0.0
+	for j := range b {
+		for i := range a {
+			if r := stringRatio(a[i], b[j]); r > best {
+				bi, bj, best = i, j, r
+			}
+		}
+	}
+	if best < 0.75 {
+		ndiffLines(buf, "- ", a)
+		ndiffLines(buf, "+ ", b)
+		return
+	}
+	ndiffReplace(buf, a[:bi], b[:bj])
+	ga, gb := ndiffGuides(trimEOL(a[bi]), trimEOL(b[bj]))
+	ndiffLines(buf, "- ", a[bi:bi+1])
+	ndiffGuide(buf, ga)
+	ndiffLines(buf, "+ ", b[bj:bj+1])
+	ndiffGuide(buf, gb)
+	ndiffReplace(buf, a[bi+1:], b[bj+1:])
+}
+
+func ndiffLines(buf *strings.Builder, prefix string, lines []string) {
+	for _, l := range lines {
+		buf.WriteString(prefix)
+		buf.WriteString(trimEOL(l))
+		buf.WriteByte('\n')
+	}
+}
+
+func ndiffGuide(buf *strings.Builder, guide string) {
+	if guide = strings.TrimRight(guide, " "); guide != "" {
+		buf.WriteString("? ")
+		buf.WriteString(guide)
+		buf.WriteByte('\n')
+	}
+}
+
+// ndiffGuides returns the guides marking the rune differences of a and b.
+func ndiffGuides(a, b string) (string, string) {
+	ra, rb := []rune(a), []rune(b)
+	ga, gb := []byte(strings.Repeat(" ", len(ra))), []byte(strings.Repeat(" ", len(rb)))
+	for _, c := range Runes(ra, rb) {
+		ma, mb := byte('-'), byte('+')
+		if c.Del > 0 && c.Ins > 0 {
+			ma, mb = '^', '^'
+		}
+		for i := c.A; i < c.A+c.Del; i++ {
+			ga[i] = ma
+		}
+		for i := c.B; i < c.B+c.Ins; i++ {
+			gb[i] = mb
+		}
+	}
+	return string(ga), string(gb)
+}
+
+// trimEOL returns l without a trailing line ending.
+func trimEOL(l string) string {
+	l = strings.TrimSuffix(l, "\n")
+	return strings.TrimSuffix(l, "\r")
+}
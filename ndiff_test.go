@@ -0,0 +1,20 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestNDiff(t *testing.T) {
+	a := diff.SplitLines("one\ntwo\nthree\n")
+	b := diff.SplitLines("ore\ntree\nemu\n")
+	// same as python: ''.join(difflib.ndiff(a, b))
+	expect := "- one\n?  ^\n+ ore\n?  ^\n- two\n- three\n?  -\n+ tree\n+ emu\n"
+	if res := diff.NDiff(a, b); res != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, res)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// DiffNGram returns the differences of a and b comparing elements as n-grams.
+// Two elements are only equal if they and the n-1 elements following them are
+// equal. Near the end of the inputs the n-grams are shorter and must have the
+// same length, so trailing elements only match at the same distance from the end.
+//
+// A larger n avoids spurious short matches in repetitive data, but reports
+// more elements as changed around each real change. An n less than 2 compares
+// single elements like Ints.
+func DiffNGram(a, b []int, n int) []Change {
+	if n < 2 {
+		return Ints(a, b)
+	}
+	return Diff(len(a), len(b), &ngrams{a, b, n})
+}
+
+type ngrams struct {
+	a, b []int
+	n    int
+}
+
+func (d *ngrams) Equal(i, j int) bool {
+	ga, gb := d.a[i:min(i+d.n, len(d.a))], d.b[j:min(j+d.n, len(d.b))]
+	if len(ga) != len(gb) {
+		return false
+	}
+	for k := range ga {
+		if ga[k] != gb[k] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,25 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestDiffNGram(t *testing.T) {
+	a := []int{1, 2, 1, 3, 1, 2, 1}
+	b := []int{1, 3, 1, 2, 1, 3}
+	res := diff.DiffNGram(a, b, 1)
+	if !diffsEqual(res, diff.Ints(a, b)) {
+		t.Error("n 1 differs from Ints", res)
+	}
+	// single repeated ones no longer match on their own
+	res = diff.DiffNGram(a, b, 2)
+	echange := []diff.Change{{0, 0, 2, 0}, {6, 4, 1, 2}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+}
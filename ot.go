@@ -0,0 +1,38 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// An OTOp is an operational transform operation.
+// Exactly one of the fields is set: retain or delete a number of runes,
+// or insert runes at the current position.
+type OTOp struct {
+	Retain int
+	Delete int
+	Insert []rune
+}
+
+// OTChangeset returns the operations transforming a into b.
+// The operations cover the whole of a in order; a replacement is a delete
+// followed by an insert.
+func OTChangeset(a, b []rune) []OTOp {
+	var ops []OTOp
+	x := 0
+	for _, c := range Runes(a, b) {
+		if x < c.A {
+			ops = append(ops, OTOp{Retain: c.A - x})
+		}
+		if c.Del > 0 {
+			ops = append(ops, OTOp{Delete: c.Del})
+		}
+		if c.Ins > 0 {
+			ops = append(ops, OTOp{Insert: b[c.B : c.B+c.Ins]})
+		}
+		x = c.A + c.Del
+	}
+	if x < len(a) {
+		ops = append(ops, OTOp{Retain: len(a) - x})
+	}
+	return ops
+}
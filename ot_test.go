@@ -0,0 +1,36 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"reflect"
+	"testing"
+)
+
+func TestOTChangeset(t *testing.T) {
+	a, b := []rune("hello wörld"), []rune("hallo wörld!")
+	expect := []diff.OTOp{
+		{Retain: 1},
+		{Delete: 1},
+		{Insert: []rune("a")},
+		{Retain: 9},
+		{Insert: []rune("!")},
+	}
+	ops := diff.OTChangeset(a, b)
+	if !reflect.DeepEqual(ops, expect) {
+		t.Errorf("expected %v got %v", expect, ops)
+	}
+	// applying the ops to a results in b
+	var res []rune
+	for _, op := range ops {
+		res = append(res, a[:op.Retain]...)
+		a = a[op.Retain+op.Delete:]
+		res = append(res, op.Insert...)
+	}
+	if string(res) != string(b) {
+		t.Errorf("expected %q got %q", string(b), string(res))
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import "strings"
+
+// DiffPaths returns the differences of two path listings at the granularity
+// of their first depth components, for example to see which top level
+// directories of a tree changed. Paths are split at slashes and empty
+// components from leading, trailing or repeated slashes are ignored, so
+// "/usr//lib/" has the components usr and lib. Backslashes are no separators.
+// Consecutive paths with the same first depth components are coalesced into
+// one group and the groups are diffed. The changes cover all paths of the
+// changed groups and refer to the positions in a and b.
+// A depth of 0 or less diffs the full paths.
+func DiffPaths(a, b []string, depth int) []Change {
+	if depth <= 0 {
+		return Diff(len(a), len(b), &stringSlices{a, b})
+	}
+	ka, sa := collapsePaths(a, depth)
+	kb, sb := collapsePaths(b, depth)
+	changes := Diff(len(ka), len(kb), &stringSlices{ka, kb})
+	for i, c := range changes {
+		changes[i] = Change{sa[c.A], sb[c.B], sa[c.A+c.Del] - sa[c.A], sb[c.B+c.Ins] - sb[c.B]}
+	}
+	return changes
+}
+
+// collapsePaths returns the groups of paths with the same first depth
+// components and the start of each group followed by the end of paths.
+func collapsePaths(paths []string, depth int) (keys []string, starts []int) {
+	for i, p := range paths {
+		var comps []string
+		for _, c := range strings.Split(p, "/") {
+			if c != "" && len(comps) < depth {
+				comps = append(comps, c)
+			}
+		}
+		k := strings.Join(comps, "/")
+		if l := len(keys); l > 0 && keys[l-1] == k {
+			continue
+		}
+		keys = append(keys, k)
+		starts = append(starts, i)
+	}
+	return keys, append(starts, len(paths))
+}
@@ -0,0 +1,28 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestDiffPaths(t *testing.T) {
+	a := []string{"/bin/ls", "/etc/hosts", "/etc/passwd", "/usr//lib/a", "/usr/lib/b"}
+	b := []string{"/bin/ls", "/bin/cat", "/usr/lib/a", "/usr/lib/c", "/var/log/"}
+	echange := []diff.Change{{1, 2, 2, 0}, {5, 4, 0, 1}}
+	if res := diff.DiffPaths(a, b, 1); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	echange = []diff.Change{{1, 1, 2, 1}, {5, 4, 0, 1}}
+	if res := diff.DiffPaths(a, b, 2); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	// full paths are compared as they are
+	echange = []diff.Change{{1, 1, 4, 4}}
+	if res := diff.DiffPaths(a, b, 0); !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import "sort"
+
+// A Rename pairs the removed entry at index Removed with the added entry at
+// index Added. An index of -1 means the other entry has no counterpart and
+// was only removed or added.
+type Rename struct {
+	Removed, Added int
+	Similarity     float64
+}
+
+// DetectRenames pairs removed with added entries, like paths or file contents,
+// that are at least threshold similar, like git diff -M.
+// similar returns the similarity of a removed and an added entry from 0 to 1.
+// It is called for every combination of removed and added entries.
+// The pairs are chosen greedily: the most similar pair is taken first, then
+// the most similar pair of the remaining entries, and so on. Ties go to the
+// lower removed and then added index.
+// The result has an entry for every removed entry in order, followed by the
+// added entries that were not paired.
+func DetectRenames(removed, added []string, similar func(a, b string) float64, threshold float64) []Rename {
+	var cands []Rename
+	for i, r := range removed {
+		for j, a := range added {
+			if s := similar(r, a); s >= threshold {
+				cands = append(cands, Rename{i, j, s})
+			}
+		}
+	}
+	sort.SliceStable(cands, func(i, j int) bool {
+		return cands[i].Similarity > cands[j].Similarity
+	})
+	res := make([]Rename, len(removed))
+	for i := range res {
+		res[i] = Rename{i, -1, 0}
+	}
+	paired := make([]bool, len(added))
+	for _, c := range cands {
+		if res[c.Removed].Added < 0 && !paired[c.Added] {
+			res[c.Removed] = c
+			paired[c.Added] = true
+		}
+	}
+	for j, p := range paired {
+		if !p {
+			res = append(res, Rename{-1, j, 0})
+		}
+	}
+	return res
+}
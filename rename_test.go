@@ -0,0 +1,34 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"reflect"
+	"testing"
+)
+
+func TestDetectRenames(t *testing.T) {
+	removed := []string{"one two three", "alpha beta", "gone"}
+	added := []string{"alpha beta gamma", "new", "one two three"}
+	// the share of the longer entry that is a common prefix
+	similar := func(a, b string) float64 {
+		n := 0
+		for n < len(a) && n < len(b) && a[n] == b[n] {
+			n++
+		}
+		return float64(n) / float64(max(len(a), len(b)))
+	}
+	res := diff.DetectRenames(removed, added, similar, 0.5)
+	expect := []diff.Rename{
+		{0, 2, 1},
+		{1, 0, 10.0 / 16},
+		{2, -1, 0},
+		{-1, 1, 0},
+	}
+	if !reflect.DeepEqual(res, expect) {
+		t.Errorf("expected %v got %v", expect, res)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// A Row is a row of a side by side view of two inputs. Equal rows have both
+// columns, deleted rows only Left and inserted rows only Right.
+type Row struct {
+	Left, Right string
+	Kind        Kind
+}
+
+// Rows returns the rows of a side by side view of the changes of the lines a
+// and b. Within a change that deletes and inserts lines, the deleted lines are
+// paired in order with the inserted lines in Replace rows, the first with the
+// first and so on. The surplus lines of the longer side follow in Delete or
+// Insert rows.
+func Rows(changes []Change, a, b []string) []Row {
+	var res []Row
+	x := 0
+	eq := func(end int) {
+		for ; x < end; x++ {
+			res = append(res, Row{a[x], a[x], Equal})
+		}
+	}
+	for _, c := range changes {
+		eq(c.A)
+		p := min(c.Del, c.Ins)
+		for i := 0; i < p; i++ {
+			res = append(res, Row{a[c.A+i], b[c.B+i], Replace})
+		}
+		for i := p; i < c.Del; i++ {
+			res = append(res, Row{Left: a[c.A+i], Kind: Delete})
+		}
+		for i := p; i < c.Ins; i++ {
+			res = append(res, Row{Right: b[c.B+i], Kind: Insert})
+		}
+		x = c.A + c.Del
+	}
+	eq(len(a))
+	return res
+}
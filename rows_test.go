@@ -0,0 +1,36 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"reflect"
+	"testing"
+)
+
+func TestRows(t *testing.T) {
+	a := []string{"a", "b", "c", "d", "e"}
+	b := []string{"a", "x", "y", "z", "d", "f"}
+	changes := []diff.Change{{1, 1, 2, 3}, {4, 5, 1, 1}}
+	expect := []diff.Row{
+		{"a", "a", diff.Equal},
+		{"b", "x", diff.Replace},
+		{"c", "y", diff.Replace},
+		{"", "z", diff.Insert},
+		{"d", "d", diff.Equal},
+		{"e", "f", diff.Replace},
+	}
+	if res := diff.Rows(changes, a, b); !reflect.DeepEqual(res, expect) {
+		t.Errorf("expected %v got %v", expect, res)
+	}
+	expect = []diff.Row{
+		{"a", "", diff.Delete},
+		{"b", "b", diff.Equal},
+		{"", "c", diff.Insert},
+	}
+	if res := diff.Rows([]diff.Change{{0, 0, 1, 0}, {2, 1, 0, 1}}, []string{"a", "b"}, []string{"b", "c"}); !reflect.DeepEqual(res, expect) {
+		t.Errorf("expected %v got %v", expect, res)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// A Segment is a run of equal, deleted, inserted or replaced elements.
+type Segment struct {
+	Kind       Kind
+	A, B       int // position in input a and b
+	ALen, BLen int // length in input a and b, the same for Equal
+}
+
+// DiffFull returns the differences of data as segments including the equal runs.
+// The segments cover both sequences from start to end without gaps or overlaps.
+func DiffFull(n, m int, data Data) []Segment {
+	return Segments(n, m, Diff(n, m, data))
+}
+
+// Segments returns the segments of changes for inputs of length n and m.
+// The changes must be ordered by ascending positions as returned by this package.
+func Segments(n, m int, changes []Change) []Segment {
+	res := make([]Segment, 0, 2*len(changes)+1)
+	x, y := 0, 0
+	for _, c := range changes {
+		if x < c.A {
+			res = append(res, Segment{Equal, x, y, c.A - x, c.A - x})
+		}
+		res = append(res, Segment{c.Kind(), c.A, c.B, c.Del, c.Ins})
+		x, y = c.A+c.Del, c.B+c.Ins
+	}
+	if x < n || y < m {
+		res = append(res, Segment{Equal, x, y, n - x, m - y})
+	}
+	return res
+}
+
+// A Snake is a maximal run of equal elements in the alignment.
+type Snake struct {
+	A, B int // position in input a and b
+	Len  int
+}
+
+// DiffSnakes returns the differences of data and the snakes between them.
+// Together the changes and snakes cover both sequences without gaps.
+func DiffSnakes(n, m int, data Data) ([]Change, []Snake) {
+	changes := Diff(n, m, data)
+	var snakes []Snake
+	x, y := 0, 0
+	for _, c := range changes {
+		if x < c.A {
+			snakes = append(snakes, Snake{x, y, c.A - x})
+		}
+		x, y = c.A+c.Del, c.B+c.Ins
+	}
+	if x < n {
+		snakes = append(snakes, Snake{x, y, n - x})
+	}
+	return changes, snakes
+}
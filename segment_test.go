@@ -0,0 +1,69 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestDiffFull(t *testing.T) {
+	for _, test := range tests {
+		segs := diff.DiffFull(len(test.a), len(test.b), &ints{test.a, test.b})
+		x, y, changes := 0, 0, 0
+		for _, s := range segs {
+			if s.A != x || s.B != y {
+				t.Error(test.name, "gap or overlap at", s)
+			}
+			switch s.Kind {
+			case diff.Equal:
+				if s.ALen != s.BLen {
+					t.Error(test.name, "equal segment lengths differ", s)
+				}
+			case diff.Delete:
+				if s.ALen == 0 || s.BLen != 0 {
+					t.Error(test.name, "bad delete segment", s)
+				}
+			case diff.Insert:
+				if s.ALen != 0 || s.BLen == 0 {
+					t.Error(test.name, "bad insert segment", s)
+				}
+			case diff.Replace:
+				if s.ALen == 0 || s.BLen == 0 {
+					t.Error(test.name, "bad replace segment", s)
+				}
+			}
+			if s.Kind != diff.Equal {
+				changes++
+			}
+			x += s.ALen
+			y += s.BLen
+		}
+		if x != len(test.a) || y != len(test.b) {
+			t.Error(test.name, "segments end at", x, y)
+		}
+		if changes != len(diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "expected a segment per change", segs)
+		}
+	}
+}
+
+func TestDiffSnakes(t *testing.T) {
+	a := []int{1, 2, 3, 1, 2, 2, 1}
+	b := []int{3, 2, 1, 2, 1, 3}
+	changes, snakes := diff.DiffSnakes(len(a), len(b), &ints{a, b})
+	if !diffsEqual(changes, diff.Ints(a, b)) {
+		t.Error("changes differ from Ints", changes)
+	}
+	expect := []diff.Snake{{1, 1, 1}, {3, 2, 2}, {6, 4, 1}}
+	if len(snakes) != len(expect) {
+		t.Fatal("expected", expect, "got", snakes)
+	}
+	for i := range snakes {
+		if snakes[i] != expect[i] {
+			t.Error("expected", expect[i], "got", snakes[i])
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// DiffSkipping returns the differences of data without the positions in
+// skipA and skipB, for example lines with timestamps or build ids at known
+// positions. The skipped elements are removed from the inputs before the
+// diff and never compared, and the positions of the result are mapped back
+// to the original inputs.
+//
+// A skipped position is not part of a change unless it lies between the
+// first and last element of a change, whose range then spans it. Insertions
+// are placed before the next element that is not skipped. The result is
+// therefore not an exact edit script of the original inputs.
+func DiffSkipping(n, m int, data Data, skipA, skipB map[int]bool) []Change {
+	ia, ib := unskipped(n, skipA), unskipped(m, skipB)
+	changes := Diff(len(ia), len(ib), &skippedData{data, ia, ib})
+	for i, c := range changes {
+		changes[i] = Change{
+			skippedOrigin(ia, c.A, n), skippedOrigin(ib, c.B, m),
+			skippedSpan(ia, c.A, c.Del), skippedSpan(ib, c.B, c.Ins),
+		}
+	}
+	return changes
+}
+
+type skippedData struct {
+	data Data
+	a, b []int
+}
+
+func (d *skippedData) Equal(i, j int) bool { return d.data.Equal(d.a[i], d.b[j]) }
+
+// unskipped returns the positions below n that are not in skip.
+func unskipped(n int, skip map[int]bool) []int {
+	res := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		if !skip[i] {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// skippedOrigin returns the original position of p or n past the end.
+func skippedOrigin(idx []int, p, n int) int {
+	if p < len(idx) {
+		return idx[p]
+	}
+	return n
+}
+
+// skippedSpan returns the original length of the l elements at p.
+func skippedSpan(idx []int, p, l int) int {
+	if l == 0 {
+		return 0
+	}
+	return idx[p+l-1] + 1 - idx[p]
+}
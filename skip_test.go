@@ -0,0 +1,35 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestDiffSkipping(t *testing.T) {
+	// the first element is a volatile header
+	a := []int{100, 1, 2, 3, 4}
+	b := []int{200, 1, 5, 3, 4, 6}
+	res := diff.DiffSkipping(len(a), len(b), &ints{a, b}, map[int]bool{0: true}, map[int]bool{0: true})
+	echange := []diff.Change{{2, 2, 1, 1}, {5, 5, 0, 1}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	// a skipped position inside a change is spanned
+	a = []int{1, 2, 9, 3, 4}
+	b = []int{1, 4}
+	res = diff.DiffSkipping(len(a), len(b), &ints{a, b}, map[int]bool{2: true}, nil)
+	echange = []diff.Change{{1, 1, 3, 0}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	for _, test := range tests {
+		res := diff.DiffSkipping(len(test.a), len(test.b), &ints{test.a, test.b}, nil, nil)
+		if !diffsEqual(res, diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "differs from Ints", res)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// StatIgnoringMoves counts the deleted and inserted elements of changes,
+// but counts a deleted element that equals an inserted one as a move instead.
+// Elements are matched by data.Equal, so moves are exact, and each deleted
+// element is paired with the first unpaired equal insertion. The pairing
+// compares every deleted with every inserted element in the worst case.
+func StatIgnoringMoves(changes []Change, data Data) (dels, ins, moves int) {
+	var inserted []int
+	for _, c := range changes {
+		for j := c.B; j < c.B+c.Ins; j++ {
+			inserted = append(inserted, j)
+		}
+		ins += c.Ins
+	}
+	for _, c := range changes {
+		for i := c.A; i < c.A+c.Del; i++ {
+			for k, j := range inserted {
+				if j >= 0 && data.Equal(i, j) {
+					inserted[k] = -1
+					moves++
+					break
+				}
+			}
+		}
+		dels += c.Del
+	}
+	return dels - moves, ins - moves, moves
+}
@@ -0,0 +1,21 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestStatIgnoringMoves(t *testing.T) {
+	// 1 moves to the end, 3 is replaced by 9 and 8 is added
+	a := []int{1, 2, 3, 4, 5}
+	b := []int{2, 9, 4, 5, 1, 8}
+	d := &ints{a, b}
+	dels, ins, moves := diff.StatIgnoringMoves(diff.Diff(len(a), len(b), d), d)
+	if dels != 1 || ins != 2 || moves != 1 {
+		t.Error("expected 1 2 1 got", dels, ins, moves)
+	}
+}
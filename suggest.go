@@ -0,0 +1,24 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// A Suggestion replaces the lines StartLine to EndLine of the original with
+// the Replacement lines, in the shape of GitHub's suggested changes.
+// Line numbers are 1-based and inclusive. An insertion has an empty range
+// with EndLine one less than StartLine, the replacement goes before StartLine.
+// A deletion has no replacement lines.
+type Suggestion struct {
+	StartLine, EndLine int
+	Replacement        []string
+}
+
+// Suggestions returns a suggestion for each of the changes of the lines a and b.
+func Suggestions(changes []Change, a, b []string) []Suggestion {
+	res := make([]Suggestion, 0, len(changes))
+	for _, c := range changes {
+		res = append(res, Suggestion{c.A + 1, c.A + c.Del, b[c.B : c.B+c.Ins]})
+	}
+	return res
+}
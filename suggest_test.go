@@ -0,0 +1,25 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"reflect"
+	"testing"
+)
+
+func TestSuggestions(t *testing.T) {
+	a := []string{"a", "b", "c", "d"}
+	b := []string{"x", "a", "c", "e", "f"}
+	changes := []diff.Change{{0, 0, 0, 1}, {1, 2, 1, 0}, {3, 3, 1, 2}}
+	expect := []diff.Suggestion{
+		{1, 0, []string{"x"}},
+		{2, 2, []string{}},
+		{4, 4, []string{"e", "f"}},
+	}
+	if res := diff.Suggestions(changes, a, b); !reflect.DeepEqual(res, expect) {
+		t.Errorf("expected %v got %v", expect, res)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"context"
+	"time"
+)
+
+// DiffTimeout returns the differences of data like Diff, but stops searching
+// once timeout has passed. The deadline is checked for every d of the middle
+// snake search. A region that is searched when the deadline passes, and any
+// region after it, is reported as a single change replacing it. Regions that
+// were finished before keep their minimal changes, so the result is always a
+// valid diff that is only coarse where the time ran out.
+// A timeout of 0 or less means no limit.
+func DiffTimeout(n, m int, data Data, timeout time.Duration) []Change {
+	var d Differ
+	if timeout > 0 {
+		deadline := time.Now().Add(timeout)
+		d.c.cancel = func() bool { return time.Now().After(deadline) }
+	}
+	return d.Diff(n, m, data)
+}
+
+// cancelCheck is the number of search steps between checks of a context.
+const cancelCheck = 64
+
+// DiffContext returns the differences of data like Diff, but stops and returns
+// ctx.Err() if ctx is done before the diff is complete. The context is checked
+// every few d of the middle snake searches, so a long diff stops soon after
+// ctx is canceled without slowing down the search.
+func DiffContext(ctx context.Context, n, m int, data Data) ([]Change, error) {
+	var d Differ
+	var steps int
+	var err error
+	d.c.cancel = func() bool {
+		if steps++; err == nil && steps%cancelCheck == 0 {
+			err = ctx.Err()
+		}
+		return err != nil
+	}
+	res := d.Diff(n, m, data)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
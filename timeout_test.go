@@ -0,0 +1,86 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"context"
+	"github.com/mb0/diff"
+	"testing"
+	"time"
+)
+
+func TestDiffTimeout(t *testing.T) {
+	for _, test := range tests {
+		res := diff.DiffTimeout(len(test.a), len(test.b), &ints{test.a, test.b}, time.Minute)
+		if !diffsEqual(res, diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "differs from Ints", res)
+		}
+	}
+	// the common prefix and suffix are eaten before the expired search
+	a, b := make([]int, 100), make([]int, 100)
+	for i := range a {
+		a[i], b[i] = i, i
+	}
+	for i := 40; i < 60; i++ {
+		b[i] = -1
+	}
+	b[50] = 50
+	res := diff.DiffTimeout(len(a), len(b), &slowInts{ints: ints{a, b}}, time.Millisecond)
+	echange := []diff.Change{{40, 40, 20, 20}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+}
+
+// slowInts takes a while for the first comparison.
+type slowInts struct {
+	ints
+	slept bool
+}
+
+func (d *slowInts) Equal(i, j int) bool {
+	if !d.slept {
+		time.Sleep(2 * time.Millisecond)
+		d.slept = true
+	}
+	return d.ints.Equal(i, j)
+}
+
+func TestDiffContext(t *testing.T) {
+	for _, test := range tests {
+		res, err := diff.DiffContext(context.Background(), len(test.a), len(test.b), &ints{test.a, test.b})
+		if err != nil || !diffsEqual(res, diff.Ints(test.a, test.b)) {
+			t.Error(test.name, "differs from Ints", res, err)
+		}
+	}
+	// completely different inputs need the full search
+	a, b := make([]int, 20000), make([]int, 20000)
+	for i := range a {
+		a[i], b[i] = i%7, i%5
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	data := &cancelingInts{ints: ints{a, b}, cancel: cancel, after: 100000}
+	res, err := diff.DiffContext(ctx, len(a), len(b), data)
+	if err != context.Canceled || res != nil {
+		t.Fatal("expected canceled got", err, len(res))
+	}
+	if data.calls > 10*data.after {
+		t.Error("expected to stop soon after cancel got", data.calls, "calls")
+	}
+}
+
+// cancelingInts calls cancel after a number of comparisons.
+type cancelingInts struct {
+	ints
+	cancel       func()
+	calls, after int
+}
+
+func (d *cancelingInts) Equal(i, j int) bool {
+	if d.calls++; d.calls == d.after {
+		d.cancel()
+	}
+	return d.ints.Equal(i, j)
+}
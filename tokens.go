@@ -0,0 +1,60 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// A TokenFunc splits source code into lexical tokens like identifiers,
+// operators, whitespace and string literals. The tokens must concatenate
+// to the source without anything left out.
+type TokenFunc func(src string) []string
+
+// DiffTokens returns the differences of the tokens of a and b as split by
+// lang, so that a renamed identifier is one changed token. The changes cover
+// whole tokens, but their positions and lengths are byte offsets into a and b,
+// so a[c.A:c.A+c.Del] is the deleted source. A nil lang uses WhitespaceTokens.
+func DiffTokens(a, b string, lang TokenFunc) []Change {
+	if lang == nil {
+		lang = WhitespaceTokens
+	}
+	d := &stringSlices{lang(a), lang(b)}
+	oa, ob := tokenOffsets(d.a), tokenOffsets(d.b)
+	changes := Diff(len(d.a), len(d.b), d)
+	for i, c := range changes {
+		changes[i] = Change{oa[c.A], ob[c.B], oa[c.A+c.Del] - oa[c.A], ob[c.B+c.Ins] - ob[c.B]}
+	}
+	return changes
+}
+
+// WhitespaceTokens splits s into alternating runs of whitespace and other runes.
+func WhitespaceTokens(s string) []string {
+	var res []string
+	for len(s) > 0 {
+		r, n := utf8.DecodeRuneInString(s)
+		space := unicode.IsSpace(r)
+		for n < len(s) {
+			r, size := utf8.DecodeRuneInString(s[n:])
+			if unicode.IsSpace(r) != space {
+				break
+			}
+			n += size
+		}
+		res = append(res, s[:n])
+		s = s[n:]
+	}
+	return res
+}
+
+// tokenOffsets returns the byte offset of each token and the end offset.
+func tokenOffsets(tokens []string) []int {
+	res := make([]int, len(tokens)+1)
+	for i, t := range tokens {
+		res[i+1] = res[i] + len(t)
+	}
+	return res
+}
@@ -0,0 +1,49 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"reflect"
+	"testing"
+)
+
+func TestWhitespaceTokens(t *testing.T) {
+	expect := []string{"x", " ", "=", "\t ", "föo(1)", "\n"}
+	if res := diff.WhitespaceTokens("x =\t föo(1)\n"); !reflect.DeepEqual(res, expect) {
+		t.Errorf("expected %q got %q", expect, res)
+	}
+}
+
+func TestDiffTokens(t *testing.T) {
+	a := "total := count + 1"
+	b := "total := num + 1"
+	res := diff.DiffTokens(a, b, nil)
+	echange := []diff.Change{{9, 9, 5, 3}}
+	if !diffsEqual(res, echange) {
+		t.Error("expected", echange, "got", res)
+	}
+	// split identifiers and punctuation
+	lang := func(src string) []string {
+		var res []string
+		for i := 0; i < len(src); {
+			j := i + 1
+			for isIdent(src[i]) && j < len(src) && isIdent(src[j]) {
+				j++
+			}
+			res = append(res, src[i:j])
+			i = j
+		}
+		return res
+	}
+	a, b = "f(alpha,beta)", "f(alpha,gamma)"
+	res = diff.DiffTokens(a, b, lang)
+	echange = []diff.Change{{8, 8, 4, 5}}
+	if !diffsEqual(res, echange) || a[8:12] != "beta" || b[8:13] != "gamma" {
+		t.Error("expected", echange, "got", res)
+	}
+}
+
+func isIdent(c byte) bool { return c >= 'a' && c <= 'z' }
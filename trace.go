@@ -0,0 +1,20 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// Trace returns the furthest reaching forward d-paths of the middle snake
+// search over the whole input, for example to animate the edit graph.
+// Element i of row d is the x endpoint of diagonal k = 2*i-d, with y = x-k.
+// The search stops in the row where it finds the middle snake, so the last
+// row may end early. Unlike Diff, the common prefix and suffix are not eaten
+// first and only the top level search is traced. Empty inputs have no trace.
+func Trace(n, m int, data Data) [][]int {
+	if n == 0 || m == 0 {
+		return nil
+	}
+	c := state{data: data, max: n + m + 1, tracing: true}
+	c.findMiddleSnake(0, 0, n, m)
+	return c.trace
+}
@@ -0,0 +1,29 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"reflect"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	// paper fig. 1
+	a, b := "abcabba", "cbabac"
+	res := diff.Trace(len(a), len(b), &stringData{a, b})
+	// the middle snake is found on diagonal 1 before diagonal 3 is reached
+	expect := [][]int{{0}, {0, 1}, {2, 2, 3}, {3, 4, 5}}
+	if !reflect.DeepEqual(res, expect) {
+		t.Errorf("expected %v got %v", expect, res)
+	}
+	if res := diff.Trace(0, 3, panicData{}); res != nil {
+		t.Error("expected no trace got", res)
+	}
+}
+
+type stringData struct{ a, b string }
+
+func (d *stringData) Equal(i, j int) bool { return d.a[i] == d.b[j] }
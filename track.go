@@ -0,0 +1,43 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// A TrackedChange is a change with an id that is kept across diffs.
+type TrackedChange struct {
+	Change
+	ID int
+}
+
+// TrackChanges assigns ids to the changes curr, keeping the ids of the
+// previous changes prev they correspond to. Both must be diffs against the
+// same input a, like a review base while b evolves, ordered as returned by
+// this package.
+//
+// A change corresponds to a previous one if their ranges in a overlap or
+// touch, so a change that grew or shrank keeps its id. Each previous id is
+// used at most once, by the first corresponding change. The other changes get
+// new ids counting up from the largest previous id, starting at 1.
+func TrackChanges(prev []TrackedChange, curr []Change) []TrackedChange {
+	next := 1
+	for _, p := range prev {
+		next = max(next, p.ID+1)
+	}
+	res := make([]TrackedChange, 0, len(curr))
+	i := 0
+	for _, c := range curr {
+		// previous changes ending before c can not match later ones either
+		for i < len(prev) && prev[i].A+prev[i].Del < c.A {
+			i++
+		}
+		if i < len(prev) && prev[i].A <= c.A+c.Del {
+			res = append(res, TrackedChange{c, prev[i].ID})
+			i++
+			continue
+		}
+		res = append(res, TrackedChange{c, next})
+		next++
+	}
+	return res
+}
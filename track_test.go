@@ -0,0 +1,34 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"reflect"
+	"testing"
+)
+
+func TestTrackChanges(t *testing.T) {
+	base := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	v1 := []int{1, 0, 3, 4, 5, 6, 7, 8, 9}
+	v2 := []int{1, 0, 0, 4, 5, 7, 8, 9}
+	t1 := diff.TrackChanges(nil, diff.Ints(base, v1))
+	expect := []diff.TrackedChange{
+		{diff.Change{1, 1, 1, 1}, 1},
+		{diff.Change{8, 8, 0, 1}, 2},
+	}
+	if !reflect.DeepEqual(t1, expect) {
+		t.Errorf("expected %v got %v", expect, t1)
+	}
+	t2 := diff.TrackChanges(t1, diff.Ints(base, v2))
+	expect = []diff.TrackedChange{
+		{diff.Change{1, 1, 2, 2}, 1},
+		{diff.Change{5, 5, 1, 0}, 3},
+		{diff.Change{8, 7, 0, 1}, 2},
+	}
+	if !reflect.DeepEqual(t2, expect) {
+		t.Errorf("expected %v got %v", expect, t2)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// A Node is a node of a tree like a DOM element, syntax node or file.
+type Node interface {
+	// Label identifies the node among its siblings, like a tag or file name.
+	Label() string
+	Children() []Node
+}
+
+// A TreeChange is a removed, added or moved node.
+type TreeChange struct {
+	Op   string // one of "remove", "add" or "move"
+	A, B []int  // the child index path in a and b, nil if not in that tree
+}
+
+// DiffTree returns the differences of the trees a and b.
+// The children of each pair of matched nodes are diffed by their labels.
+// Removed and added siblings with the same label are reported as a move
+// between the siblings. Matched and moved nodes are compared recursively.
+// Paths are the child indices from the root, which has the empty path. If the
+// roots have different labels, a is removed and b added.
+func DiffTree(a, b Node) []TreeChange {
+	if a.Label() != b.Label() {
+		return []TreeChange{{"remove", []int{}, nil}, {"add", nil, []int{}}}
+	}
+	return diffTree(nil, []int{}, []int{}, a, b)
+}
+
+func diffTree(res []TreeChange, pa, pb []int, a, b Node) []TreeChange {
+	ca, cb := a.Children(), b.Children()
+	d := &nodes{ca, cb}
+	var pairs [][2]int
+	var dels, ins []int
+	x, y := 0, 0
+	for _, c := range Diff(len(ca), len(cb), d) {
+		for ; x < c.A; x, y = x+1, y+1 {
+			pairs = append(pairs, [2]int{x, y})
+		}
+		for ; x < c.A+c.Del; x++ {
+			dels = append(dels, x)
+		}
+		for ; y < c.B+c.Ins; y++ {
+			ins = append(ins, y)
+		}
+	}
+	for ; x < len(ca); x, y = x+1, y+1 {
+		pairs = append(pairs, [2]int{x, y})
+	}
+	moved := make([]bool, len(cb))
+	for _, i := range dels {
+		j := -1
+		for _, k := range ins {
+			if !moved[k] && d.Equal(i, k) {
+				j = k
+				break
+			}
+		}
+		if j < 0 {
+			res = append(res, TreeChange{"remove", childPath(pa, i), nil})
+			continue
+		}
+		moved[j] = true
+		res = append(res, TreeChange{"move", childPath(pa, i), childPath(pb, j)})
+		pairs = append(pairs, [2]int{i, j})
+	}
+	for _, j := range ins {
+		if !moved[j] {
+			res = append(res, TreeChange{"add", nil, childPath(pb, j)})
+		}
+	}
+	for _, p := range pairs {
+		res = diffTree(res, childPath(pa, p[0]), childPath(pb, p[1]), ca[p[0]], cb[p[1]])
+	}
+	return res
+}
+
+func childPath(path []int, i int) []int {
+	return append(path[:len(path):len(path)], i)
+}
+
+type nodes struct{ a, b []Node }
+
+func (d *nodes) Equal(i, j int) bool { return d.a[i].Label() == d.b[j].Label() }
@@ -0,0 +1,48 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"reflect"
+	"testing"
+)
+
+type node struct {
+	label    string
+	children []diff.Node
+}
+
+func (n *node) Label() string         { return n.label }
+func (n *node) Children() []diff.Node { return n.children }
+
+func tree(label string, children ...diff.Node) diff.Node {
+	return &node{label, children}
+}
+
+func TestDiffTree(t *testing.T) {
+	a := tree("html",
+		tree("head", tree("title")),
+		tree("body", tree("h1"), tree("p"), tree("ul", tree("li"))),
+	)
+	b := tree("html",
+		tree("head", tree("title"), tree("meta")),
+		tree("body", tree("ul", tree("li"), tree("li")), tree("h1"), tree("div")),
+	)
+	expect := []diff.TreeChange{
+		{"add", nil, []int{0, 1}},
+		{"remove", []int{1, 1}, nil},
+		{"move", []int{1, 2}, []int{1, 0}},
+		{"add", nil, []int{1, 2}},
+		{"add", nil, []int{1, 0, 1}},
+	}
+	if res := diff.DiffTree(a, b); !reflect.DeepEqual(res, expect) {
+		t.Errorf("expected %v got %v", expect, res)
+	}
+	expect = []diff.TreeChange{{"remove", []int{}, nil}, {"add", nil, []int{}}}
+	if res := diff.DiffTree(a, tree("xml")); !reflect.DeepEqual(res, expect) {
+		t.Errorf("expected %v got %v", expect, res)
+	}
+}
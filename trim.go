@@ -0,0 +1,51 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+// The helpers for concrete types trim the common prefix and suffix of their
+// inputs before calling Diff, which avoids a call to Equal for each element
+// in the hottest loops on mostly equal inputs.
+
+// trimBytes returns the lengths of the common prefix and suffix of a and b,
+// comparing word sized chunks first.
+func trimBytes[S string | []byte](a, b S) (pre, suf int) {
+	n := min(len(a), len(b))
+	for pre+8 <= n && string(a[pre:pre+8]) == string(b[pre:pre+8]) {
+		pre += 8
+	}
+	for pre < n && a[pre] == b[pre] {
+		pre++
+	}
+	la, lb := len(a), len(b)
+	for pre+suf+8 <= n && string(a[la-suf-8:la-suf]) == string(b[lb-suf-8:lb-suf]) {
+		suf += 8
+	}
+	for pre+suf < n && a[la-suf-1] == b[lb-suf-1] {
+		suf++
+	}
+	return pre, suf
+}
+
+// trimSlices returns the lengths of the common prefix and suffix of a and b.
+func trimSlices[T comparable](a, b []T) (pre, suf int) {
+	n := min(len(a), len(b))
+	for pre < n && a[pre] == b[pre] {
+		pre++
+	}
+	la, lb := len(a), len(b)
+	for pre+suf < n && a[la-suf-1] == b[lb-suf-1] {
+		suf++
+	}
+	return pre, suf
+}
+
+// shift moves the changes by off in both inputs.
+func shift(changes []Change, off int) []Change {
+	for i := range changes {
+		changes[i].A += off
+		changes[i].B += off
+	}
+	return changes
+}
@@ -0,0 +1,331 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"fmt"
+	"iter"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DiffFiles diffs the lines of the files at pathA and pathB and returns the
+// lines of the unified diff with the given number of context lines.
+// If wrap is greater than 0, content lines longer than wrap runes including
+// their prefix are soft-wrapped and each continuation repeats the prefix.
+// Wrapped output is meant for display and can no longer be applied as a patch.
+// The lines are rendered lazily as the sequence is iterated, but both files
+// are read before DiffFiles returns, so any read error is returned immediately
+// and iterating never fails. Identical files yield no lines.
+func DiffFiles(pathA, pathB string, context, wrap int) (iter.Seq[string], error) {
+	ra, err := os.ReadFile(pathA)
+	if err != nil {
+		return nil, err
+	}
+	rb, err := os.ReadFile(pathB)
+	if err != nil {
+		return nil, err
+	}
+	a, b := SplitLines(string(ra)), SplitLines(string(rb))
+	changes := Diff(len(a), len(b), &stringSlices{a, b})
+	return func(yield func(string) bool) {
+		if len(changes) == 0 {
+			return
+		}
+		if yield("--- "+pathA+"\n") && yield("+++ "+pathB+"\n") {
+			unified(a, b, changes, context, wrap, yield)
+		}
+	}, nil
+}
+
+// Unified renders the changes of the lines a and b as the hunks of a unified
+// diff with the given number of context lines. Changes that are at most
+// 2*context lines apart share a hunk.
+// The lines are expected to keep their line endings as returned by SplitLines.
+func Unified(a, b []string, changes []Change, context int) string {
+	var buf strings.Builder
+	unified(a, b, changes, context, 0, func(l string) bool {
+		buf.WriteString(l)
+		return true
+	})
+	return buf.String()
+}
+
+// ParseUnified reads the hunks of a unified diff as written by Unified and
+// returns its changes. Lines before the first hunk, like file headers, are
+// skipped. An error is returned for malformed hunk headers, hunks out of order
+// and hunks whose line counts do not match their headers.
+func ParseUnified(patch string) ([]Change, error) {
+	changes, _, err := ParseUnifiedLines(patch)
+	return changes, err
+}
+
+// ParseUnifiedLines is like ParseUnified but also returns the lines inserted by
+// each change, so that ApplyInserted(a, changes, inserted) rebuilds input b.
+// The lines keep their line endings as returned by SplitLines.
+func ParseUnifiedLines(patch string) ([]Change, [][]string, error) {
+	var changes []Change
+	var inserted [][]string
+	add := func(x, y, del, ins int) {
+		if l := len(changes) - 1; l >= 0 && changes[l].A+changes[l].Del == x && changes[l].B+changes[l].Ins == y {
+			changes[l].Del += del
+			changes[l].Ins += ins
+			return
+		}
+		changes = append(changes, Change{x, y, del, ins})
+		inserted = append(inserted, nil)
+	}
+	lines := SplitLines(patch)
+	seen := false
+	// end is the end of the last hunk in a and delta its offset in b
+	end, delta := 0, 0
+	for i := 0; i < len(lines); {
+		l := lines[i]
+		i++
+		if !strings.HasPrefix(l, "@@") {
+			if seen {
+				return nil, nil, fmt.Errorf("diff: parse unified line %d: unexpected line %q", i, l)
+			}
+			continue
+		}
+		seen = true
+		a0, alen, b0, blen, ok := parseHunkHeader(l)
+		if !ok {
+			return nil, nil, fmt.Errorf("diff: parse unified line %d: malformed hunk header %q", i, l)
+		}
+		if a0 < end || b0-a0 != delta {
+			return nil, nil, fmt.Errorf("diff: parse unified line %d: hunk out of order", i)
+		}
+		x, y := a0, b0
+		var prev byte
+		for x < a0+alen || y < b0+blen || i < len(lines) && lines[i][0] == '\\' {
+			if i == len(lines) {
+				return nil, nil, fmt.Errorf("diff: parse unified line %d: hunk ends early", i)
+			}
+			l := lines[i]
+			i++
+			switch l[0] {
+			case ' ':
+				x++
+				y++
+			case '-':
+				add(x, y, 1, 0)
+				x++
+			case '+':
+				add(x, y, 0, 1)
+				k := len(inserted) - 1
+				inserted[k] = append(inserted[k], l[1:])
+				y++
+			case '\\':
+				if prev == 0 || prev == '\\' {
+					return nil, nil, fmt.Errorf("diff: parse unified line %d: unexpected marker", i)
+				}
+				if prev == '+' {
+					ins := inserted[len(inserted)-1]
+					ins[len(ins)-1] = trimEOL(ins[len(ins)-1])
+				}
+			default:
+				return nil, nil, fmt.Errorf("diff: parse unified line %d: unexpected line %q", i, l)
+			}
+			if x > a0+alen || y > b0+blen {
+				return nil, nil, fmt.Errorf("diff: parse unified line %d: hunk longer than its header", i)
+			}
+			prev = l[0]
+		}
+		end, delta = a0+alen, b0+blen-(a0+alen)
+	}
+	return changes, inserted, nil
+}
+
+// parseHunkHeader parses the ranges of a unified hunk header.
+func parseHunkHeader(l string) (a0, alen, b0, blen int, ok bool) {
+	l, _, ok = strings.Cut(strings.TrimPrefix(l, "@@ "), " @@")
+	if !ok {
+		return
+	}
+	ra, rb, ok := strings.Cut(l, " ")
+	if !ok || !strings.HasPrefix(ra, "-") || !strings.HasPrefix(rb, "+") {
+		return 0, 0, 0, 0, false
+	}
+	if a0, alen, ok = parseRange(ra[1:]); !ok {
+		return
+	}
+	b0, blen, ok = parseRange(rb[1:])
+	return
+}
+
+// parseRange parses a hunk range as formatted by unifiedRange.
+func parseRange(s string) (start, length int, ok bool) {
+	first, count, hasCount := strings.Cut(s, ",")
+	start, err := strconv.Atoi(first)
+	if err != nil || start < 0 {
+		return 0, 0, false
+	}
+	length = 1
+	if hasCount {
+		if length, err = strconv.Atoi(count); err != nil || length < 0 {
+			return 0, 0, false
+		}
+	}
+	if length > 0 {
+		if start == 0 {
+			return 0, 0, false
+		}
+		start--
+	}
+	return start, length, true
+}
+
+// UnifiedFunc renders the changes of any two sequences of length n and m as
+// the hunks of a unified diff with the given number of context lines.
+// format returns the text of element i of input b if inB is true, or else of
+// input a, without a line ending. It is called once for every element.
+func UnifiedFunc(n, m int, changes []Change, context int, format func(i int, inB bool) string) string {
+	var buf strings.Builder
+	unified(formatLines(n, false, format), formatLines(m, true, format), changes, context, 0, func(l string) bool {
+		buf.WriteString(l)
+		return true
+	})
+	return buf.String()
+}
+
+// formatLines returns the formatted elements of one input as lines.
+func formatLines(n int, inB bool, format func(i int, inB bool) string) []string {
+	res := make([]string, n)
+	for i := range res {
+		res[i] = format(i, inB) + "\n"
+	}
+	return res
+}
+
+// A Hunk is a group of changes with the unchanged elements around them.
+type Hunk struct {
+	AStart, ALen int // range in input a
+	BStart, BLen int // range in input b
+	Changes      []Change
+}
+
+// Hunks groups changes that are at most 2*context elements apart and adds up
+// to context unchanged elements around each group, limited by the lengths n
+// and m of the inputs. It is the grouping used by the formatters.
+// The changes must be ordered by ascending positions as returned by this package.
+func Hunks(n, m, context int, changes []Change) []Hunk {
+	return HunksAsym(n, m, context, context, changes)
+}
+
+// HunksAsym is like Hunks but adds up to before unchanged elements before and
+// up to after unchanged elements after each group. Changes that are at most
+// before+after elements apart are grouped.
+func HunksAsym(n, m, before, after int, changes []Change) []Hunk {
+	var res []Hunk
+	for i := 0; i < len(changes); {
+		j := i + 1
+		for j < len(changes) && changes[j].A-(changes[j-1].A+changes[j-1].Del) <= before+after {
+			j++
+		}
+		first, last := changes[i], changes[j-1]
+		a0 := max(first.A-before, 0)
+		b0 := first.B - (first.A - a0)
+		a1 := min(last.A+last.Del+after, n)
+		b1 := last.B + last.Ins + a1 - (last.A + last.Del)
+		res = append(res, Hunk{a0, a1 - a0, b0, b1 - b0, changes[i:j]})
+		i = j
+	}
+	return res
+}
+
+// PatchSize returns the size in bytes of the unified diff of the lines a and b
+// without context lines and without the deleted lines, which a patch against
+// a known a does not need. It is the size of the hunk headers and inserted
+// lines and is computed without rendering them.
+// Lines are expected to keep their line endings as returned by SplitLines.
+func PatchSize(a, b []string) int {
+	changes := Diff(len(a), len(b), &stringSlices{a, b})
+	size := 0
+	for _, h := range Hunks(len(a), len(b), 0, changes) {
+		size += len("@@ - + @@\n") + len(unifiedRange(h.AStart, h.ALen)) + len(unifiedRange(h.BStart, h.BLen))
+		for _, l := range b[h.BStart : h.BStart+h.BLen] {
+			size += 1 + len(l)
+			if len(l) == 0 || l[len(l)-1] != '\n' {
+				size += 1 + len(NoNewline)
+			}
+		}
+	}
+	return size
+}
+
+// unified yields the hunks of the unified diff of the lines a and b.
+// The lines are expected to keep their line endings as returned by SplitLines.
+// Content lines are wrapped at wrap runes if wrap is greater than 0.
+// It returns false if yield returned false.
+func unified(a, b []string, changes []Change, context, wrap int, yield func(string) bool) bool {
+	for _, h := range Hunks(len(a), len(b), context, changes) {
+		header := "@@ -" + unifiedRange(h.AStart, h.ALen) + " +" + unifiedRange(h.BStart, h.BLen) + " @@\n"
+		if !yield(header) {
+			return false
+		}
+		x := h.AStart
+		for _, c := range h.Changes {
+			if !yieldLines(yield, " ", a[x:c.A], wrap) ||
+				!yieldLines(yield, "-", a[c.A:c.A+c.Del], wrap) ||
+				!yieldLines(yield, "+", b[c.B:c.B+c.Ins], wrap) {
+				return false
+			}
+			x = c.A + c.Del
+		}
+		if !yieldLines(yield, " ", a[x:h.AStart+h.ALen], wrap) {
+			return false
+		}
+	}
+	return true
+}
+
+// yieldLines yields each line with prefix and adds the no newline marker
+// after a line without line ending. Lines are wrapped at wrap runes if wrap
+// is greater than 0.
+func yieldLines(yield func(string) bool, prefix string, lines []string, wrap int) bool {
+	for _, l := range lines {
+		if wrap > 0 {
+			// leave at least one rune per continuation line
+			width := max(wrap-len(prefix), 1)
+			for {
+				// cut after width runes by byte offset to keep invalid utf-8 as is
+				body, off := trimEOL(l), 0
+				for n := 0; n < width && off < len(body); n++ {
+					_, size := utf8.DecodeRuneInString(body[off:])
+					off += size
+				}
+				if off == len(body) {
+					break
+				}
+				if !yield(prefix + l[:off] + "\n") {
+					return false
+				}
+				l = l[off:]
+			}
+		}
+		if len(l) == 0 || l[len(l)-1] != '\n' {
+			if !yield(prefix+l+"\n") || !yield(NoNewline) {
+				return false
+			}
+		} else if !yield(prefix + l) {
+			return false
+		}
+	}
+	return true
+}
+
+// unifiedRange formats a hunk range. Empty ranges refer to the line before.
+func unifiedRange(start, length int) string {
+	switch length {
+	case 0:
+		return strconv.Itoa(start) + ",0"
+	case 1:
+		return strconv.Itoa(start + 1)
+	}
+	return strconv.Itoa(start+1) + "," + strconv.Itoa(length)
+}
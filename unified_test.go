@@ -0,0 +1,253 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestHunks(t *testing.T) {
+	changes := []diff.Change{{1, 1, 1, 0}, {4, 3, 0, 2}, {9, 9, 1, 1}}
+	expect := []diff.Hunk{
+		{0, 5, 0, 6, changes[:2]},
+		{8, 2, 8, 2, changes[2:]},
+	}
+	if res := diff.Hunks(10, 10, 1, changes); !reflect.DeepEqual(res, expect) {
+		t.Errorf("expected %v got %v", expect, res)
+	}
+	expect = []diff.Hunk{
+		{1, 1, 1, 0, changes[:1]},
+		{4, 0, 3, 2, changes[1:2]},
+		{9, 1, 9, 1, changes[2:]},
+	}
+	if res := diff.Hunks(10, 10, 0, changes); !reflect.DeepEqual(res, expect) {
+		t.Errorf("expected %v got %v", expect, res)
+	}
+}
+
+func TestHunksAsym(t *testing.T) {
+	changes := []diff.Change{{1, 1, 1, 0}, {4, 3, 0, 2}, {9, 9, 1, 1}}
+	// the trailing context of the first change reaches the second
+	expect := []diff.Hunk{
+		{1, 5, 1, 6, changes[:2]},
+		{9, 1, 9, 1, changes[2:]},
+	}
+	if res := diff.HunksAsym(10, 10, 0, 2, changes); !reflect.DeepEqual(res, expect) {
+		t.Errorf("expected %v got %v", expect, res)
+	}
+	expect = []diff.Hunk{
+		{0, 4, 0, 5, changes[:2]},
+		{6, 4, 6, 4, changes[2:]},
+	}
+	if res := diff.HunksAsym(10, 10, 3, 0, changes); !reflect.DeepEqual(res, expect) {
+		t.Errorf("expected %v got %v", expect, res)
+	}
+}
+
+func TestDiffFiles(t *testing.T) {
+	dir := t.TempDir()
+	pa, pb := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	if err := os.WriteFile(pa, []byte("1\n2\n3\n4\n5\n6\n7\n8\n9"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pb, []byte("1\ntwo\n3\n4\n5\n6\n7\n8\n9\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	seq, err := diff.DiffFiles(pa, pb, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	for line := range seq {
+		got += line
+	}
+	expect := "--- " + pa + "\n+++ " + pb + "\n" +
+		"@@ -1,3 +1,3 @@\n 1\n-2\n+two\n 3\n" +
+		"@@ -8,2 +8,2 @@\n 8\n-9\n\\ No newline at end of file\n+9\n"
+	if got != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, got)
+	}
+	if _, err := diff.DiffFiles(filepath.Join(dir, "missing"), pb, 3, 0); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestPatchSize(t *testing.T) {
+	a := []string{"a\n", "b\n", "c\n"}
+	b := []string{"a\n", "x\n", "c"}
+	// @@ -2,2 +2,2 @@ and the inserted lines
+	expect := 16 + 3 + 3 + len(diff.NoNewline)
+	if res := diff.PatchSize(a, b); res != expect {
+		t.Error("expected", expect, "got", res)
+	}
+	if res := diff.PatchSize(a, a); res != 0 {
+		t.Error("expected 0 for equal inputs got", res)
+	}
+}
+
+func TestDiffFilesWrap(t *testing.T) {
+	dir := t.TempDir()
+	pa, pb := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	if err := os.WriteFile(pa, []byte("short\nlong line\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pb, []byte("short\nlänger line"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	seq, err := diff.DiffFiles(pa, pb, 1, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	for line := range seq {
+		got += line
+	}
+	expect := "--- " + pa + "\n+++ " + pb + "\n" +
+		"@@ -1,2 +1,2 @@\n shor\n t\n-long\n- lin\n-e\n" +
+		"+läng\n+er l\n+ine\n\\ No newline at end of file\n"
+	if got != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, got)
+	}
+}
+
+func TestDiffFilesWrapInvalidUTF8(t *testing.T) {
+	dir := t.TempDir()
+	pa, pb := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	if err := os.WriteFile(pa, []byte("short\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// latin-1 encoded text
+	if err := os.WriteFile(pb, []byte("short\n\xe9\xe9\xe9\xe9\xe9\xe9\xe9\xe9\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	seq, err := diff.DiffFiles(pa, pb, 0, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	for line := range seq {
+		got += line
+	}
+	expect := "--- " + pa + "\n+++ " + pb + "\n" +
+		"@@ -1,0 +2 @@\n+\xe9\xe9\xe9\xe9\n+\xe9\xe9\xe9\xe9\n"
+	if got != expect {
+		t.Errorf("expected\n%q\ngot\n%q", expect, got)
+	}
+}
+
+func TestUnifiedFunc(t *testing.T) {
+	a := []int{1, 2, 3, 4}
+	b := []int{1, 3, 4, 5}
+	format := func(i int, inB bool) string {
+		if inB {
+			return strconv.Itoa(b[i])
+		}
+		return strconv.Itoa(a[i])
+	}
+	res := diff.UnifiedFunc(len(a), len(b), diff.Ints(a, b), 1, format)
+	expect := "@@ -1,4 +1,4 @@\n 1\n-2\n 3\n 4\n+5\n"
+	if res != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, res)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	lines := func(s string) []string { return diff.SplitLines(s) }
+	for _, test := range []struct {
+		name    string
+		a, b    string
+		context int
+		expect  string
+	}{
+		{"merged", "a\nb\nc\nd\ne\nf\n", "a\nB\nc\nd\nE\nf\n", 1,
+			"@@ -1,6 +1,6 @@\n a\n-b\n+B\n c\n d\n-e\n+E\n f\n"},
+		{"split", "a\nb\nc\nd\ne\nf\ng\n", "a\nB\nc\nd\ne\nF\ng\n", 1,
+			"@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n@@ -5,3 +5,3 @@\n e\n-f\n+F\n g\n"},
+		{"boundaries", "a\nb\nc\n", "A\nb\nC\n", 3,
+			"@@ -1,3 +1,3 @@\n-a\n+A\n b\n-c\n+C\n"},
+		{"insert", "a\nb\n", "a\nx\nb\n", 0,
+			"@@ -1,0 +2 @@\n+x\n"},
+		{"delete", "a\nx\nb\n", "a\nb\n", 1,
+			"@@ -1,3 +1,2 @@\n a\n-x\n b\n"},
+		{"insert into empty", "", "x\ny", 3,
+			"@@ -0,0 +1,2 @@\n+x\n+y\n" + diff.NoNewline},
+		{"delete all", "x\n", "", 3,
+			"@@ -1 +0,0 @@\n-x\n"},
+		{"equal", "a\n", "a\n", 3, ""},
+	} {
+		a, b := lines(test.a), lines(test.b)
+		res := diff.Unified(a, b, diff.Strings(a, b), test.context)
+		if res != test.expect {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.name, test.expect, res)
+		}
+	}
+}
+
+func TestParseUnified(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	text := func() string {
+		var buf strings.Builder
+		for i := r.Intn(12); i > 0; i-- {
+			buf.WriteString(string(rune('a'+r.Intn(4))) + "\n")
+		}
+		if r.Intn(3) == 0 {
+			buf.WriteString("z")
+		}
+		return buf.String()
+	}
+	for k := 0; k < 200; k++ {
+		a, b := diff.SplitLines(text()), diff.SplitLines(text())
+		changes := diff.Strings(a, b)
+		patch := diff.Unified(a, b, changes, k%4)
+		res, ins, err := diff.ParseUnifiedLines("--- a\n+++ b\n" + patch)
+		if err != nil {
+			t.Fatalf("%v parsing\n%s", err, patch)
+		}
+		if !reflect.DeepEqual(res, changes) {
+			t.Fatalf("expected %v got %v parsing\n%s", changes, res, patch)
+		}
+		if got := diff.ApplyInserted(a, res, ins); !slices.Equal(got, b) {
+			t.Fatalf("expected %q got %q applying\n%s", b, got, patch)
+		}
+	}
+	for _, patch := range []string{
+		"@@ -1 +1 @@\n-a\n+b\n",
+		"@@ -0,0 +1,2 @@\n+a\n+b\n@@ -3,2 +5 @@\n c\n-d\n\\ No newline at end of file\n",
+		// far positions do not allocate placeholder lines
+		"@@ -999999999,0 +1000000000 @@\n+x\n",
+	} {
+		if _, err := diff.ParseUnified(patch); err != nil {
+			t.Errorf("unexpected error %v parsing\n%s", err, patch)
+		}
+	}
+}
+
+func TestParseUnifiedCorrupt(t *testing.T) {
+	for _, patch := range []string{
+		"@@ -1,x +1 @@\n-a\n+b\n",
+		"@@ -1 +1\n-a\n+b\n",
+		"@@ 1 +1 @@\n-a\n+b\n",
+		"@@ -0 +1 @@\n-a\n+b\n",
+		"@@ -1,2 +1,2 @@\n-a\n+b\n",
+		"@@ -1 +1 @@\n-a\n+b\n c\n",
+		"@@ -1 +1 @@\n-a\n-c\n+b\n",
+		"@@ -1 +1 @@\n*a\n+b\n",
+		"@@ -1 +1 @@\n\\ No newline at end of file\n-a\n+b\n",
+		"@@ -3 +3 @@\n-a\n+b\n@@ -1 +1 @@\n-a\n+b\n",
+		"@@ -1 +1 @@\n-a\n+b\n@@ -3 +4 @@\n-a\n+b\n",
+	} {
+		if _, err := diff.ParseUnified(patch); err == nil {
+			t.Errorf("expected error parsing\n%s", patch)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"strings"
+	"unicode"
+)
+
+// A TrailingWSMode controls how the line methods of a Differ treat trailing
+// whitespace. Trailing whitespace includes the line ending.
+type TrailingWSMode int
+
+const (
+	// ShowTrailingWS compares trailing whitespace like any other content.
+	ShowTrailingWS TrailingWSMode = iota
+	// IgnoreTrailingWS compares lines without their trailing whitespace,
+	// so changes of only trailing whitespace are not reported.
+	IgnoreTrailingWS
+	// MarkTrailingWS compares lines like ShowTrailingWS, but HTML adds the
+	// class "ws" to the lines of changes of only trailing whitespace.
+	MarkTrailingWS
+)
+
+// DiffLines returns the differences of the lines a and b and treats
+// trailing whitespace as set by d.TrailingWS.
+func (d *Differ) DiffLines(a, b []string) []Change {
+	if d.TrailingWS == IgnoreTrailingWS {
+		return d.Diff(len(a), len(b), &trimmedLines{a, b})
+	}
+	return d.Diff(len(a), len(b), &stringSlices{a, b})
+}
+
+// HTML renders the differences of the lines a and b like the package function
+// HTML and marks changes of only trailing whitespace as set by d.TrailingWS.
+func (d *Differ) HTML(a, b []string) string {
+	return htmlChanges(a, b, d.DiffLines(a, b), d.TrailingWS == MarkTrailingWS)
+}
+
+// TrailingWSOnly returns whether c replaces the lines of a with as many lines
+// of b that only differ in trailing whitespace.
+func (c Change) TrailingWSOnly(a, b []string) bool {
+	if c.Del != c.Ins {
+		return false
+	}
+	for i := 0; i < c.Del; i++ {
+		if trimWS(a[c.A+i]) != trimWS(b[c.B+i]) {
+			return false
+		}
+	}
+	return c.Del > 0
+}
+
+type trimmedLines stringSlices
+
+func (d *trimmedLines) Equal(i, j int) bool { return trimWS(d.a[i]) == trimWS(d.b[j]) }
+
+func trimWS(l string) string { return strings.TrimRightFunc(l, unicode.IsSpace) }
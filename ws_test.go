@@ -0,0 +1,54 @@
+// Copyright 2012 Martin Schnabel. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"github.com/mb0/diff"
+	"testing"
+)
+
+func TestDifferTrailingWS(t *testing.T) {
+	a := []string{"a\n", "b \n", "c\n"}
+	b := []string{"a\n", "b\n", "x\n"}
+	var d diff.Differ
+	echange := []diff.Change{{1, 1, 2, 2}}
+	if res := d.DiffLines(a, b); !diffsEqual(res, echange) {
+		t.Error("show expected", echange, "got", res)
+	}
+	d.TrailingWS = diff.IgnoreTrailingWS
+	echange = []diff.Change{{2, 2, 1, 1}}
+	if res := d.DiffLines(a, b); !diffsEqual(res, echange) {
+		t.Error("ignore expected", echange, "got", res)
+	}
+	d.TrailingWS = diff.MarkTrailingWS
+	b[2] = "c"
+	expect := `<div class="eq">a</div>
+<div class="del ws">b<del> </del></div>
+<div class="ins ws">b</div>
+<div class="del ws">c</div>
+<div class="ins ws">c</div>
+`
+	if res := d.HTML(a, b); res != expect {
+		t.Errorf("mark expected\n%s got\n%s", expect, res)
+	}
+}
+
+func TestChangeTrailingWSOnly(t *testing.T) {
+	a := []string{"a \t\n", "b", "c"}
+	b := []string{"a", "b\r\n", "d"}
+	for _, test := range []struct {
+		c      diff.Change
+		expect bool
+	}{
+		{diff.Change{0, 0, 2, 2}, true},
+		{diff.Change{0, 0, 3, 3}, false},
+		{diff.Change{0, 0, 1, 0}, false},
+		{diff.Change{3, 3, 0, 0}, false},
+	} {
+		if res := test.c.TrailingWSOnly(a, b); res != test.expect {
+			t.Error(test.c, "expected", test.expect, "got", res)
+		}
+	}
+}